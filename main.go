@@ -2,22 +2,104 @@ package main
 
 import (
 	"api-server/pkg/handlers"
+	"api-server/pkg/k8s"
 	"api-server/pkg/services"
+	"api-server/pkg/storage"
+	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func main() {
 	// 의존성 주입
-	logService := services.NewInMemoryLogService()
+	logStorage, jobStore, err := newStorage()
+	if err != nil {
+		log.Fatal(err)
+	}
+	logService := services.NewLogService(logStorage)
+	jobService := services.NewJobService(jobStore, logService)
+
+	// 업로드된 빌드 컨텍스트 저장소
+	contexts, err := newContextStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// 보관 기간이 지난 Job의 로그와 종료된 Job의 빌드 컨텍스트 정리
+	retention, err := logRetention()
+	if err != nil {
+		log.Fatal(err)
+	}
+	go services.NewLogReaper(logService, jobService, retention, services.WithBuildContexts(contexts)).Run(context.Background())
+
+	// Kubernetes 클라이언트 (클러스터에 연결할 수 없으면 job.yaml 생성만 수행)
+	var jobCreator handlers.KubernetesJobCreator
+	clientset, err := k8s.NewClientset()
+	if err != nil {
+		log.Printf("Kubernetes client unavailable, jobs will only be written to jobs/: %v", err)
+	} else {
+		jobCreator = k8s.NewJobClient(clientset, namespace())
+
+		// 빌드 Pod의 컨테이너 로그 수집 및 Job 상태 추적
+		// 여러 replica가 저장소를 공유하면 리더로 선출된 replica만 수행
+		collect := func(ctx context.Context) {
+			go k8s.NewLogCollector(clientset, namespace(), logService).Run(ctx)
+			k8s.NewStatusTracker(clientset, namespace(), logService, k8s.WithGitCommits(jobService)).Run(ctx)
+		}
+		if os.Getenv("LEADER_ELECTION") == "true" {
+			identity, _ := os.Hostname()
+			go k8s.RunAsLeader(context.Background(), clientset, namespace(), identity, collect)
+		} else {
+			go collect(context.Background())
+		}
+	}
+
+	// 요청에 cache가 없을 때 적용할 빌드 캐시 기본 정책
+	cachePolicy, err := buildCachePolicy()
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// 핸들러 생성
-	jobHandler := handlers.NewBuildJobHandler(logService)
-	logsHandler := handlers.NewLogsHandler(logService)
+	jobOptions := []handlers.BuildJobOption{
+		handlers.WithNamespace(namespace()),
+		handlers.WithBuildContexts(contexts, contextBaseURL()),
+		handlers.WithCachePolicy(cachePolicy),
+	}
+	// 빌드 secrets/ssh로 마운트할 수 있는 Secret (쉼표로 구분)
+	if value := os.Getenv("BUILD_SECRETS_ALLOWED"); value != "" {
+		var names []string
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		jobOptions = append(jobOptions, handlers.WithAllowedBuildSecrets(names...))
+	}
+	if os.Getenv("JOB_NAME_MODE") == "generate" {
+		jobOptions = append(jobOptions, handlers.WithGeneratedJobNames())
+	}
+	jobHandler := handlers.NewBuildJobHandler(logService, jobService, jobCreator, jobOptions...)
+	logsHandler := handlers.NewLogsHandler(logService, jobService)
+	statusHandler := handlers.NewStatusHandler(logService, jobService)
 
 	// BuildJob API 라우팅
-	http.HandleFunc("/api/buildjob", jobHandler.Create)
-	http.HandleFunc("/api/buildjob/", logsHandler.Get)
+	http.HandleFunc("GET /api/buildjob", jobHandler.List)
+	http.HandleFunc("POST /api/buildjob", jobHandler.Create)
+	http.HandleFunc("GET /api/buildjob/{job}", jobHandler.Get)
+	http.HandleFunc("DELETE /api/buildjob/{job}", jobHandler.Cancel)
+	http.HandleFunc("POST /api/buildjob/{job}/cancel", jobHandler.Cancel)
+	http.HandleFunc("GET /api/buildjob/{job}/context", jobHandler.Context)
+	http.HandleFunc("/api/buildjob/{job}/logs", logsHandler.Get)
+	http.HandleFunc("/api/buildjob/{job}/logs/ws", logsHandler.WebSocket)
+	http.HandleFunc("/api/buildjob/{job}/status", statusHandler.Get)
 
 	log.Println("Server starting on http://localhost:8080")
 	if err := http.ListenAndServe(":8080", nil); err != nil {
@@ -25,3 +107,181 @@ func main() {
 	}
 }
 
+// namespace는 빌드 Job을 생성할 네임스페이스를 반환합니다
+func namespace() string {
+	if ns := os.Getenv("BUILD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	return "default"
+}
+
+// newStorage는 LOG_STORAGE 환경 변수에 따라 로그 저장소와 Job 저장소를 생성합니다
+//   - memory (기본값): 재시작 시 로그와 Job이 사라짐
+//     LOG_MAX_LINES_PER_JOB, LOG_MAX_BYTES_PER_JOB, LOG_MEMORY_BUDGET으로 메모리 사용량을 제한
+//   - file: LOG_STORAGE_DIR (기본값: data/logs) 아래에 Job별 세그먼트 파일로 로그 저장
+//   - sqlite: SQLITE_PATH (기본값: data/api-server.db)에 로그와 Job 저장
+//   - postgres: POSTGRES_URL의 데이터베이스에 로그와 Job 저장 (여러 replica가 공유 가능)
+//     POSTGRES_MAX_CONNS로 replica별 최대 연결 수를 설정
+//   - redis: REDIS_URL의 Redis 스트림에 로그 저장
+//     Job 메타데이터는 replica별 메모리에 저장하므로 replica 하나로만 실행해야 함
+//     REDIS_KEY_PREFIX로 키 접두사를 설정
+func newStorage() (storage.LogStorage, storage.JobStore, error) {
+	switch backend := os.Getenv("LOG_STORAGE"); backend {
+	case "", "memory":
+		opts, err := memoryStorageOptions()
+		if err != nil {
+			return nil, nil, err
+		}
+		logStorage := storage.NewMemoryStorage(opts...)
+		return logStorage, storage.NewMemoryJobStore(logStorage.GetStatus), nil
+	case "file":
+		dir := os.Getenv("LOG_STORAGE_DIR")
+		if dir == "" {
+			dir = "data/logs"
+		}
+		log.Printf("Storing logs under %s", dir)
+		logStorage, err := storage.NewFileStorage(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		return logStorage, storage.NewMemoryJobStore(logStorage.GetStatus), nil
+	case "sqlite":
+		path := os.Getenv("SQLITE_PATH")
+		if path == "" {
+			path = "data/api-server.db"
+		}
+		log.Printf("Storing logs and jobs in %s", path)
+		sqlStorage, err := storage.NewSQLiteStorage(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sqlStorage, sqlStorage, nil
+	case "postgres":
+		dsn := os.Getenv("POSTGRES_URL")
+		if dsn == "" {
+			return nil, nil, fmt.Errorf("POSTGRES_URL is required when LOG_STORAGE=postgres")
+		}
+		var opts []storage.PostgresOption
+		if value := os.Getenv("POSTGRES_MAX_CONNS"); value != "" {
+			maxConns, err := strconv.Atoi(value)
+			if err != nil || maxConns < 1 {
+				return nil, nil, fmt.Errorf("invalid POSTGRES_MAX_CONNS %q: must be a positive integer", value)
+			}
+			opts = append(opts, storage.WithMaxConns(maxConns))
+		}
+		log.Println("Storing logs and jobs in postgres")
+		sqlStorage, err := storage.NewPostgresStorage(dsn, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sqlStorage, sqlStorage, nil
+	case "redis":
+		url := os.Getenv("REDIS_URL")
+		if url == "" {
+			return nil, nil, fmt.Errorf("REDIS_URL is required when LOG_STORAGE=redis")
+		}
+		var opts []storage.RedisStorageOption
+		if prefix := os.Getenv("REDIS_KEY_PREFIX"); prefix != "" {
+			opts = append(opts, storage.WithKeyPrefix(prefix))
+		}
+		log.Println("Storing logs in redis")
+		logStorage, err := storage.NewRedisStorage(url, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		return logStorage, storage.NewMemoryJobStore(logStorage.GetStatus), nil
+	default:
+		return nil, nil, fmt.Errorf("unknown LOG_STORAGE %q: must be memory, file, sqlite, postgres or redis", backend)
+	}
+}
+
+// memoryStorageOptions는 메모리 저장소의 사용량 제한을 환경 변수에서 읽습니다
+// 크기는 Kubernetes 수량 형식(예: 16Mi)으로 지정합니다
+func memoryStorageOptions() ([]storage.MemoryStorageOption, error) {
+	var opts []storage.MemoryStorageOption
+
+	if value := os.Getenv("LOG_MAX_LINES_PER_JOB"); value != "" {
+		lines, err := strconv.Atoi(value)
+		if err != nil || lines < 0 {
+			return nil, fmt.Errorf("invalid LOG_MAX_LINES_PER_JOB %q: must be a non-negative integer", value)
+		}
+		opts = append(opts, storage.WithMaxLinesPerJob(lines))
+	}
+
+	for name, option := range map[string]func(int64) storage.MemoryStorageOption{
+		"LOG_MAX_BYTES_PER_JOB": storage.WithMaxBytesPerJob,
+		"LOG_MEMORY_BUDGET":     storage.WithMemoryBudget,
+	} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil || quantity.Sign() < 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be a size such as 16Mi", name, value)
+		}
+		opts = append(opts, option(quantity.Value()))
+	}
+	return opts, nil
+}
+
+// logRetention은 종료된 Job의 로그 보관 기간을 LOG_RETENTION 환경 변수(기본값: 24h)에서 읽습니다
+// 빌드 Job이 남아 있는 동안 로그가 사라지지 않도록 Job의 ttlSecondsAfterFinished보다 짧게 설정할 수 없습니다
+func logRetention() (time.Duration, error) {
+	retention := 24 * time.Hour
+	if value := os.Getenv("LOG_RETENTION"); value != "" {
+		var err error
+		if retention, err = time.ParseDuration(value); err != nil {
+			return 0, fmt.Errorf("invalid LOG_RETENTION %q: %w", value, err)
+		}
+	}
+
+	if minimum := k8s.JobTTLSecondsAfterFinished * time.Second; retention < minimum {
+		log.Printf("LOG_RETENTION %s is shorter than the build job TTL, using %s", retention, minimum)
+		retention = minimum
+	}
+	return retention, nil
+}
+
+// newContextStore는 CONTEXT_DIR (기본값: data/contexts) 아래에 업로드된 빌드 컨텍스트를 보관하는 저장소를 생성합니다
+// CONTEXT_MAX_SIZE로 업로드할 수 있는 tar.gz의 최대 크기(기본값: 100Mi)를 설정합니다
+func newContextStore() (*storage.ContextStore, error) {
+	dir := os.Getenv("CONTEXT_DIR")
+	if dir == "" {
+		dir = "data/contexts"
+	}
+
+	var opts []storage.ContextStoreOption
+	if value := os.Getenv("CONTEXT_MAX_SIZE"); value != "" {
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil || quantity.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid CONTEXT_MAX_SIZE %q: must be a size such as 100Mi", value)
+		}
+		opts = append(opts, storage.WithContextMaxSize(quantity.Value()))
+	}
+	return storage.NewContextStore(dir, opts...)
+}
+
+// contextBaseURL은 빌드 Pod가 업로드된 컨텍스트를 내려받을 api-server 주소를 반환합니다
+func contextBaseURL() string {
+	if url := os.Getenv("CONTEXT_BASE_URL"); url != "" {
+		return url
+	}
+	return "http://api-server:8080"
+}
+
+// buildCachePolicy는 빌드 캐시 기본 정책을 환경 변수에서 읽습니다
+// BUILD_CACHE는 none(기본값), registry, local, inline 중 하나이며 BUILD_CACHE_MODE로 min/max를 지정합니다
+// registry 캐시는 BUILD_CACHE_REGISTRY 저장소에, local 캐시는 BUILD_CACHE_PVC 볼륨에 저장합니다
+func buildCachePolicy() (k8s.CachePolicy, error) {
+	policy := k8s.CachePolicy{
+		Type:      os.Getenv("BUILD_CACHE"),
+		Mode:      os.Getenv("BUILD_CACHE_MODE"),
+		Registry:  os.Getenv("BUILD_CACHE_REGISTRY"),
+		ClaimName: os.Getenv("BUILD_CACHE_PVC"),
+	}
+	if err := policy.Validate(); err != nil {
+		return k8s.CachePolicy{}, fmt.Errorf("invalid build cache configuration: %w", err)
+	}
+	return policy, nil
+}
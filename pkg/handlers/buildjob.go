@@ -1,33 +1,106 @@
 package handlers
 
 import (
+	"api-server/pkg/k8s"
 	"api-server/pkg/models"
 	"api-server/pkg/services"
+	"api-server/pkg/storage"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
-// Kubernetes 클라이언트 타입 (빌드 타임 선택적 임포트)
-// 개발 환경에서만 사용되고, 프로덕션에서는 nil로 처리됨
+// KubernetesJobCreator는 빌드 Job을 클러스터에 제출하는 인터페이스입니다
+// 클러스터에 연결할 수 없는 개발 환경에서는 nil을 주입하며, 이 경우 job.yaml만 생성됩니다
 type KubernetesJobCreator interface {
-	Create(ctx context.Context, jobName, dockerfileContent string) error
+	Create(ctx context.Context, jobID string, req models.BuildJobRequest) error
+}
+
+// KubernetesJobDeleter는 실행 중인 빌드 Job을 클러스터에서 삭제하는 인터페이스입니다
+// KubernetesJobCreator 구현체가 함께 구현하면 취소 시 사용됩니다
+type KubernetesJobDeleter interface {
+	Delete(ctx context.Context, jobID, jobName string) error
 }
 
 // BuildJobHandler는 BuildJob API 핸들러입니다
 type BuildJobHandler struct {
 	logService services.LogService
+	jobService services.JobService
+	jobCreator KubernetesJobCreator
+	namespace  string
+
+	// generateJobNames가 true이면 요청된 이름으로부터 고유한 이름을 생성합니다
+	generateJobNames bool
+
+	// cachePolicy는 요청에 cache가 없을 때 적용하는 기본 캐시 정책입니다
+	cachePolicy k8s.CachePolicy
+
+	// allowedSecrets는 secrets, ssh로 마운트할 수 있는 Secret 이름입니다 (비어 있으면 모두 거부)
+	allowedSecrets map[string]bool
+
+	// contexts는 업로드된 빌드 컨텍스트 저장소입니다 (nil이면 업로드 불가)
+	contexts       *storage.ContextStore
+	contextBaseURL string
+}
+
+// BuildJobOption은 BuildJobHandler의 선택적 설정입니다
+type BuildJobOption func(*BuildJobHandler)
+
+// WithGeneratedJobNames는 요청된 job_name을 검증하는 대신
+// 정리된 고유 이름(예: my-app-x7k2p)을 서버에서 생성하도록 설정합니다
+func WithGeneratedJobNames() BuildJobOption {
+	return func(h *BuildJobHandler) {
+		h.generateJobNames = true
+	}
+}
+
+// WithNamespace는 빌드 Job을 생성하는 네임스페이스를 설정합니다 (기본값: default)
+func WithNamespace(namespace string) BuildJobOption {
+	return func(h *BuildJobHandler) {
+		h.namespace = namespace
+	}
+}
+
+// WithCachePolicy는 빌드 캐시의 서버 기본 정책을 설정합니다 (기본값: 캐시 사용 안 함)
+func WithCachePolicy(policy k8s.CachePolicy) BuildJobOption {
+	return func(h *BuildJobHandler) {
+		h.cachePolicy = policy
+	}
+}
+
+// WithAllowedBuildSecrets는 빌드 요청의 secrets, ssh에서 참조할 수 있는 Secret을 설정합니다
+// 설정하지 않으면 빌드 Secret과 SSH 포워딩 요청은 모두 거부됩니다
+func WithAllowedBuildSecrets(names ...string) BuildJobOption {
+	return func(h *BuildJobHandler) {
+		h.allowedSecrets = map[string]bool{}
+		for _, name := range names {
+			h.allowedSecrets[name] = true
+		}
+	}
 }
 
 // NewBuildJobHandler는 새로운 BuildJobHandler를 생성합니다
-func NewBuildJobHandler(logService services.LogService) *BuildJobHandler {
-	return &BuildJobHandler{
+func NewBuildJobHandler(logService services.LogService, jobService services.JobService, jobCreator KubernetesJobCreator, opts ...BuildJobOption) *BuildJobHandler {
+	h := &BuildJobHandler{
 		logService: logService,
+		jobService: jobService,
+		jobCreator: jobCreator,
+		namespace:  "default",
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	return h
 }
 
 // Create은 POST /api/buildjob를 처리합니다
@@ -43,17 +116,20 @@ func (h *BuildJobHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 
-	var req models.BuildJobRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		w.WriteHeader(http.StatusBadRequest)
+	req, staged, err := h.decodeBuildJobRequest(r)
+	if err != nil {
+		status, message := decodeErrorStatus(err)
+		w.WriteHeader(status)
 		json.NewEncoder(w).Encode(models.ErrorResponse{
-			Error: "Invalid request body",
+			Error: message,
 		})
 		return
 	}
+	// Job에 연결되지 않은 컨텍스트는 응답 후 삭제
+	defer func() { h.discard(staged) }()
 
-	// 필수 필드 검증
-	if req.JobName == "" || req.DockerfileContent == "" {
+	// 필수 필드 검증 (Git 저장소나 업로드된 컨텍스트에 Dockerfile이 있으면 dockerfile_content 생략 가능)
+	if req.JobName == "" || (req.DockerfileContent == "" && req.Git == nil && (staged == nil || !staged.HasDockerfile)) {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(models.ErrorResponse{
 			Error: "job_name and dockerfile_content are required",
@@ -61,135 +137,340 @@ func (h *BuildJobHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 로그 초기화
-	h.logService.CreateJobLogs(req.JobName)
+	// 재시도 판별을 위해 이름 생성 전의 원본 요청 내용을 기록
+	hash := requestHash(req)
+
+	// Job 이름은 Kubernetes 리소스 이름, 이미지 이름, jobs/ 파일 경로로 사용되므로 엄격히 검증
+	if h.generateJobNames {
+		req.JobName = k8s.GenerateJobName(req.JobName)
+	} else if violations := k8s.ValidateJobName(req.JobName); len(violations) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error:   fmt.Sprintf("invalid job_name %q", req.JobName),
+			Details: violations,
+		})
+		return
+	}
+
+	// 이미지 참조 검증 (registry/repository:tag)
+	imageReference, err := k8s.ImageReference(req.JobName, req.ImageName)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if req.PushRegistry && req.ImageName == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "image_name is required when push_registry is true",
+		})
+		return
+	}
+
+	if req.RegistrySecret != "" && len(validation.IsDNS1123Subdomain(req.RegistrySecret)) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: fmt.Sprintf("invalid registry_secret %q", req.RegistrySecret),
+		})
+		return
+	}
+
+	violations := append(k8s.ValidateBuildOptions(req), k8s.ValidateBuildSecrets(req)...)
+	violations = append(violations, k8s.ValidateBuildSecretAccess(req, h.allowedSecrets)...)
+	if len(violations) > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error:   "invalid build options",
+			Details: violations,
+		})
+		return
+	}
+
+	// Git 저장소 검증 (업로드된 컨텍스트와 함께 사용할 수 없음)
+	if req.Git != nil {
+		err := k8s.ValidateGitSource(*req.Git)
+		if err == nil && req.Context != nil {
+			err = errors.New("git and an uploaded build context cannot be used together")
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+	}
+
+	// 캐시 설정 결정 (scope를 지정하지 않으면 같은 이름의 Job끼리 캐시를 공유)
+	cacheScope := req.JobName
+	if h.generateJobNames {
+		cacheScope = cacheScope[:strings.LastIndex(cacheScope, "-")]
+	}
+	if req.Cache, err = k8s.ResolveCache(req, cacheScope, h.cachePolicy); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	// Job 등록 (같은 이름의 Job 충돌 및 Idempotency-Key 재시도 확인)
+	job := models.Job{
+		Name:           req.JobName,
+		ImageName:      imageReference,
+		Namespace:      h.namespace,
+		CreatedAt:      time.Now().Format(time.RFC3339),
+		IdempotencyKey: r.Header.Get("Idempotency-Key"),
+		RequestHash:    hash,
+	}
+	if req.Git != nil {
+		job.GitURL, job.GitRef = req.Git.URL, req.Git.Ref
+	}
+	job, err = h.jobService.Register(job)
+	switch {
+	case errors.Is(err, services.ErrIdempotentReplay):
+		w.Header().Set("Idempotent-Replayed", "true")
+		// 최초 요청이 실패했다면 생성 응답을 재현하지 않고 새 키로 다시 제출하도록 알림
+		if state, _ := h.logService.GetJobStatus(job.ID); state.Status == models.JobStatusFailed {
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: fmt.Sprintf("job %q created with this Idempotency-Key has failed, retry with a new key", job.Name),
+				JobID: job.ID,
+			})
+			return
+		}
+		// 재시도된 요청에는 최초 응답을 그대로 반환
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(buildJobResponse(job))
+		return
+	case errors.Is(err, services.ErrIdempotencyKeyReused):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Idempotency-Key was already used with a different request",
+			JobID: job.ID,
+		})
+		return
+	case errors.Is(err, services.ErrJobNameConflict):
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: fmt.Sprintf("job %q already exists", req.JobName),
+			JobID: job.ID,
+		})
+		return
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: fmt.Sprintf("Failed to register job: %v", err),
+		})
+		return
+	}
+
+	// 업로드된 컨텍스트를 Job에 연결
+	if staged != nil {
+		token, err := h.contexts.Commit(staged, job.ID)
+		if err != nil {
+			h.logService.AddLog(job.ID, "system", fmt.Sprintf("Failed to store build context: %v", err))
+			h.logService.SetJobStatus(job.ID, models.JobStatusFailed)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: fmt.Sprintf("Failed to store build context: %v", err),
+				JobID: job.ID,
+			})
+			return
+		}
+		staged = nil
+
+		req.Context.URL = h.contextBaseURL + "/api/buildjob/" + job.ID + "/context"
+		req.Context.Token = token
+		h.logService.AddLog(job.ID, "system", fmt.Sprintf("Build context uploaded (%d bytes, sha256:%s)", req.Context.Size, req.Context.Digest))
+	}
+	if req.Git != nil {
+		ref := req.Git.Ref
+		if ref == "" {
+			ref = "HEAD"
+		}
+		h.logService.AddLog(job.ID, "system", fmt.Sprintf("Building from git repository %s (ref %s)", req.Git.URL, ref))
+	}
+	if req.Cache != nil {
+		h.logService.AddLog(job.ID, "system", "Using "+k8s.CacheDescription(req.Cache))
+	}
 
 	// job.yaml 생성
-	if err := createJobYAML(req.JobName, req.DockerfileContent); err != nil {
+	if err := createJobYAML(h.namespace, job.ID, req); err != nil {
+		h.logService.AddLog(job.ID, "system", fmt.Sprintf("Failed to create job.yaml: %v", err))
+		h.logService.SetJobStatus(job.ID, models.JobStatusFailed)
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(models.ErrorResponse{
 			Error: fmt.Sprintf("Failed to create job.yaml: %v", err),
+			JobID: job.ID,
 		})
 		return
 	}
 
-	// Kubernetes Job 생성 시도 (클러스터 환경에서만 작동)
+	// Kubernetes Job 생성 (클러스터에 연결된 경우에만)
 	// 개발/테스트 환경에서는 스킵되고, YAML 파일만 생성됨
-	if err := createKubernetesJob(req.JobName, req.DockerfileContent); err != nil {
-		// 개발 환경에서는 에러 무시, 프로덕션에서는 로깅
-		h.logService.AddLog(req.JobName, "system", "Kubernetes Job deployment ready (use kubectl apply or Helm)")
-	} else {
-		h.logService.AddLog(req.JobName, "system", "Kubernetes Job deployment completed")
-	}
-
-	response := models.BuildJobResponse{
-		Status:    "created",
-		Message:   "Build job created successfully",
-		JobName:   req.JobName,
-		JobID:     fmt.Sprintf("build-%s-%d", req.JobName, time.Now().Unix()),
-		Namespace: "default",
-		CreatedAt: time.Now().Format(time.RFC3339),
+	if h.jobCreator != nil {
+		if err := h.jobCreator.Create(r.Context(), job.ID, req); err != nil {
+			h.logService.AddLog(job.ID, "system", fmt.Sprintf("Kubernetes Job deployment failed: %v", err))
+			// 실패 상태로 종료시켜 같은 이름으로 다시 제출할 수 있도록 함
+			h.logService.SetJobStatus(job.ID, models.JobStatusFailed)
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: fmt.Sprintf("Failed to create Kubernetes job: %v", err),
+				JobID: job.ID,
+			})
+			return
+		}
+		h.logService.AddLog(job.ID, "system", "Kubernetes Job deployment completed")
 	}
 
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(buildJobResponse(job))
 }
 
-// createJobYAML은 Kubernetes Job을 위한 job.yaml 파일을 생성합니다
-func createJobYAML(jobName, dockerfileContent string) error {
-	// jobs 디렉토리가 없으면 생성
-	if err := os.MkdirAll("jobs", 0755); err != nil {
-		return err
+// Get은 GET /api/buildjob/{job_id}를 처리합니다
+// job_id 대신 Job 이름을 사용하면 가장 최근에 제출된 Job을 조회합니다
+func (h *BuildJobHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Only GET method is allowed",
+		})
+		return
 	}
 
-	yamlContent := fmt.Sprintf(`apiVersion: batch/v1
-kind: Job
-metadata:
-  name: %s
-  namespace: default
-spec:
-  ttlSecondsAfterFinished: 300
-  backoffLimit: 3
-  template:
-    spec:
-      restartPolicy: Never
-      initContainers:
-        - name: prepare
-          image: busybox:latest
-          command:
-            - sh
-            - -c
-            - cat > /workspace/Dockerfile << 'EOFLINE'
-%s
-EOFLINE
-          securityContext:
-            runAsUser: 1000
-            runAsGroup: 1000
-          volumeMounts:
-            - name: workspace
-              mountPath: /workspace
-      containers:
-        - name: buildkit
-          image: moby/buildkit:master-rootless
-          imagePullPolicy: IfNotPresent
-          env:
-            - name: BUILDKITD_FLAGS
-              value: --oci-worker-no-process-sandbox
-          command:
-            - buildctl-daemonless.sh
-          args:
-            - build
-            - --frontend
-            - dockerfile.v0
-            - --local
-            - context=/workspace
-            - --local
-            - dockerfile=/workspace
-            - --output
-            - type=image,name=%s:latest,push=false
-          securityContext:
-            seccompProfile:
-              type: Unconfined
-            runAsUser: 1000
-            runAsGroup: 1000
-          volumeMounts:
-            - name: workspace
-              readOnly: true
-              mountPath: /workspace
-            - name: buildkitd
-              mountPath: /home/user/.local/share/buildkit
-      volumes:
-        - name: workspace
-          emptyDir: {}
-        - name: buildkitd
-          emptyDir: {}
-`, jobName, dockerfileContent, jobName)
-
-	filePath := filepath.Join("jobs", fmt.Sprintf("%s.yaml", jobName))
-	return os.WriteFile(filePath, []byte(yamlContent), 0644)
+	w.Header().Set("Content-Type", "application/json")
+
+	job, exists := findJob(h.jobService, jobKeyFromPath(r.URL.Path, ""))
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Job not found",
+		})
+		return
+	}
+
+	state, _ := h.logService.GetJobStatus(job.ID)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(jobDetail(job, state, time.Now()))
 }
 
-// createKubernetesJob은 Kubernetes Job을 생성하려 시도합니다 (in-cluster 환경에서만)
-func createKubernetesJob(jobName, dockerfileContent string) error {
-	// 주석: Kubernetes client-go는 복잡한 의존성을 가지고 있어서
-	// 개발 환경에서는 선택적으로 로드합니다.
-	// 실제 클러스터에 배포할 때는 별도의 빌드 태그를 사용합니다.
-	
-	// 여기서는 YAML 파일 생성이 주목적이고,
-	// 실제 배포는 KIND 테스트 환경에서 kubectl이나 Helm을 사용합니다.
-	
-	return nil // 개발 환경에서는 skip
+// Cancel은 DELETE /api/buildjob/{job_id} 및 POST /api/buildjob/{job_id}/cancel을 처리합니다
+// purge_logs=true이면 취소 후 로그, 상태와 Job 메타데이터를 함께 삭제하며, 이미 종료된 Job은 삭제만 가능합니다
+func (h *BuildJobHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete && r.Method != http.MethodPost {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Only DELETE or POST method is allowed",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	job, exists := findJob(h.jobService, jobKeyFromPath(r.URL.Path, "/cancel"))
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Job not found",
+		})
+		return
+	}
+
+	purgeLogs := r.URL.Query().Get("purge_logs") == "true"
+
+	state, _ := h.logService.GetJobStatus(job.ID)
+	if state.Status.IsTerminal() && !purgeLogs {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: fmt.Sprintf("job %q already finished with status %s", job.Name, state.Status),
+			JobID: job.ID,
+		})
+		return
+	}
+
+	if !state.Status.IsTerminal() {
+		if deleter, ok := h.jobCreator.(KubernetesJobDeleter); ok {
+			if err := deleter.Delete(r.Context(), job.ID, job.Name); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(models.ErrorResponse{
+					Error: fmt.Sprintf("Failed to delete Kubernetes job: %v", err),
+					JobID: job.ID,
+				})
+				return
+			}
+		}
+
+		h.logService.AddLog(job.ID, "system", "Build job cancelled by user request")
+		h.logService.SetJobStatus(job.ID, models.JobStatusCancelled)
+		state, _ = h.logService.GetJobStatus(job.ID)
+	}
+
+	// 상태가 없는 Job은 목록 필터와 보관 기간 정리 대상에서 빠지므로 메타데이터도 함께 삭제
+	if purgeLogs {
+		h.logService.DeleteJobLogs(job.ID)
+		h.jobService.Delete(job.ID)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(models.JobStatusResponse{
+		JobID:     job.ID,
+		JobName:   job.Name,
+		Status:    state.Status,
+		UpdatedAt: state.UpdatedAt,
+	})
 }
 
-// Helper 함수들
-func int32Ptr(i int32) *int32 {
-	return &i
+// buildJobResponse는 등록된 Job으로부터 생성 응답을 만듭니다
+func buildJobResponse(job models.Job) models.BuildJobResponse {
+	return models.BuildJobResponse{
+		Status:    "created",
+		Message:   "Build job created successfully",
+		JobName:   job.Name,
+		JobID:     job.ID,
+		ImageName: job.ImageName,
+		Namespace: job.Namespace,
+		CreatedAt: job.CreatedAt,
+	}
 }
 
-func int64Ptr(i int64) *int64 {
-	return &i
+// requestHash는 Idempotency-Key 재사용 여부를 판별하기 위한 요청 내용의 해시입니다
+// 업로드된 컨텍스트는 내용의 digest로 비교합니다
+func requestHash(req models.BuildJobRequest) string {
+	body, _ := json.Marshal(req)
+	hash := sha256.New()
+	hash.Write(body)
+	if req.Context != nil {
+		hash.Write([]byte(req.Context.Digest))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
 }
 
-func boolPtr(b bool) *bool {
-	return &b
+// createJobYAML은 Kubernetes Job을 위한 jobs/<job_name>-<job_id>.yaml 파일을 생성합니다
+// Dockerfile ConfigMap과 Job이 multi-document YAML로 함께 기록되며, 같은 이름으로 다시 빌드해도 이전 파일을 덮어쓰지 않습니다
+// 빌드 컨텍스트 내려받기 토큰은 Secret으로만 제출되므로 파일에 기록되지 않습니다
+func createJobYAML(namespace, jobID string, req models.BuildJobRequest) error {
+	manifest, err := k8s.NewManifest(namespace, jobID, req)
+	if err != nil {
+		return err
+	}
+
+	yamlContent, err := manifest.YAML()
+	if err != nil {
+		return err
+	}
+
+	// jobs 디렉토리가 없으면 생성
+	if err := os.MkdirAll("jobs", 0755); err != nil {
+		return err
+	}
+
+	filePath := filepath.Join("jobs", fmt.Sprintf("%s-%s.yaml", req.JobName, jobID))
+	return os.WriteFile(filePath, yamlContent, 0644)
 }
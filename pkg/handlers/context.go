@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"api-server/pkg/models"
+	"api-server/pkg/storage"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxRequestPartSize는 multipart 요청의 request 파트(JSON) 최대 크기입니다
+const maxRequestPartSize = 1024 * 1024
+
+var (
+	// errInvalidBody는 요청 JSON을 읽을 수 없을 때 반환됩니다
+	errInvalidBody = errors.New("invalid request body")
+	// errInvalidMultipart는 multipart 요청의 구성이 올바르지 않을 때 반환됩니다
+	errInvalidMultipart = errors.New("invalid multipart request")
+)
+
+// WithBuildContexts는 multipart 요청으로 빌드 컨텍스트(tar.gz)를 업로드할 수 있도록 설정합니다
+// 빌드 Pod는 baseURL(예: http://api-server:8080)의 GET /api/buildjob/{job_id}/context로 컨텍스트를 내려받습니다
+func WithBuildContexts(store *storage.ContextStore, baseURL string) BuildJobOption {
+	return func(h *BuildJobHandler) {
+		h.contexts = store
+		h.contextBaseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// decodeBuildJobRequest는 JSON 또는 multipart/form-data 요청 본문을 읽습니다
+//
+// multipart 요청은 다음 파트로 구성됩니다
+//   - request: BuildJobRequest JSON
+//   - context: 빌드 컨텍스트 tar.gz (선택, request 파트 뒤에 위치)
+//
+// 컨텍스트는 검증 후 임시로 저장되며, 호출자가 Commit 또는 Discard 해야 합니다
+func (h *BuildJobHandler) decodeBuildJobRequest(r *http.Request) (models.BuildJobRequest, *storage.StagedContext, error) {
+	var req models.BuildJobRequest
+
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return req, nil, errInvalidBody
+		}
+		return req, nil, nil
+	}
+
+	if h.contexts == nil {
+		return req, nil, fmt.Errorf("%w: build context upload is not enabled", errInvalidMultipart)
+	}
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return req, nil, fmt.Errorf("%w: %v", errInvalidMultipart, err)
+	}
+
+	decoded := false
+	var staged *storage.StagedContext
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			h.discard(staged)
+			return req, nil, fmt.Errorf("%w: %v", errInvalidMultipart, err)
+		}
+
+		switch part.FormName() {
+		case "request":
+			if err := json.NewDecoder(io.LimitReader(part, maxRequestPartSize)).Decode(&req); err != nil {
+				h.discard(staged)
+				return req, nil, fmt.Errorf("%w: request part is not valid JSON", errInvalidMultipart)
+			}
+			decoded = true
+		case "context":
+			if staged != nil {
+				h.discard(staged)
+				return req, nil, fmt.Errorf("%w: only one context part is allowed", errInvalidMultipart)
+			}
+			if staged, err = h.contexts.Stage(part); err != nil {
+				return req, nil, err
+			}
+		}
+		part.Close()
+	}
+
+	if !decoded {
+		h.discard(staged)
+		return req, nil, fmt.Errorf("%w: request part is required", errInvalidMultipart)
+	}
+	if staged != nil {
+		req.Context = &models.BuildContext{Digest: staged.Digest, Size: staged.Size}
+	}
+	return req, staged, nil
+}
+
+// discard는 등록되지 않은 빌드 컨텍스트를 삭제합니다
+func (h *BuildJobHandler) discard(staged *storage.StagedContext) {
+	if staged != nil {
+		h.contexts.Discard(staged)
+	}
+}
+
+// decodeErrorStatus는 요청 본문을 읽다 발생한 에러의 응답 상태 코드와 메시지를 반환합니다
+func decodeErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, storage.ErrContextTooLarge):
+		return http.StatusRequestEntityTooLarge, err.Error()
+	case errors.Is(err, storage.ErrInvalidContext), errors.Is(err, errInvalidMultipart):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, errInvalidBody):
+		return http.StatusBadRequest, "Invalid request body"
+	default:
+		return http.StatusInternalServerError, "Failed to store build context: " + err.Error()
+	}
+}
+
+// Context는 GET /api/buildjob/{job_id}/context를 처리합니다
+// 빌드 Pod의 prepare 컨테이너가 Authorization: Bearer <token>으로 업로드된 컨텍스트를 내려받습니다
+func (h *BuildJobHandler) Context(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Only GET method is allowed",
+		})
+		return
+	}
+
+	notFound := func() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Build context not found",
+		})
+	}
+
+	// 토큰은 Job ID별로 발급되어 공유 컨텍스트 저장소에 함께 보관되므로
+	// Job 저장소를 거치지 않고 검증함 (다른 replica나 재시작 이후에 등록된 Job도 내려받을 수 있음)
+	jobID := jobKeyFromPath(r.URL.Path, "/context")
+	token, hasToken := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !hasToken || h.contexts == nil {
+		notFound()
+		return
+	}
+
+	file, err := h.contexts.Open(jobID, token)
+	if err != nil {
+		notFound()
+		return
+	}
+	defer file.Close()
+
+	// 종료된 Job의 컨텍스트는 곧 정리되므로 더 이상 제공하지 않음
+	if state, _ := h.logService.GetJobStatus(jobID); state.Status.IsTerminal() {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusGone)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: fmt.Sprintf("job already finished with status %s", state.Status),
+			JobID: jobID,
+		})
+		return
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		notFound()
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	http.ServeContent(w, r, "", info.ModTime(), file)
+}
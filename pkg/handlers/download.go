@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"api-server/pkg/models"
+	"api-server/pkg/storage"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// 로그 응답 형식
+const (
+	logFormatJSON   = "json"
+	logFormatText   = "text"
+	logFormatNDJSON = "ndjson"
+)
+
+// negotiateLogFormat은 format 파라미터 또는 Accept 헤더로 로그 응답 형식을 결정합니다
+// 반환값에 .gz 접미사가 붙으면 gzip으로 압축하여 내려받습니다
+func negotiateLogFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "application/x-ndjson"):
+		return logFormatNDJSON
+	case strings.Contains(accept, "text/plain"):
+		return logFormatText
+	case strings.Contains(accept, "application/gzip"):
+		return logFormatText + ".gz"
+	}
+	return logFormatJSON
+}
+
+// download는 로그를 text 또는 NDJSON 형식으로 저장소에서 바로 스트리밍합니다
+//
+// text 형식은 한 줄에 "timestamp [container] level message" 입니다
+func (h *LogsHandler) download(w http.ResponseWriter, jobID, jobName string, query storage.LogQuery, format string) {
+	compressed := strings.HasSuffix(format, ".gz")
+	format = strings.TrimSuffix(format, ".gz")
+
+	var contentType, extension string
+	switch format {
+	case logFormatText:
+		contentType, extension = "text/plain; charset=utf-8", "log"
+	case logFormatNDJSON:
+		contentType, extension = "application/x-ndjson", "ndjson"
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: fmt.Sprintf("invalid format %q: must be one of json, text, ndjson, text.gz, ndjson.gz", format),
+		})
+		return
+	}
+
+	var out io.Writer
+	var buffered *bufio.Writer
+	var gz *gzip.Writer
+
+	// 첫 로그를 쓰기 전까지는 404 응답을 보낼 수 있도록 헤더를 늦게 기록
+	start := func() {
+		if compressed {
+			w.Header().Set("Content-Type", "application/gzip")
+			w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s.gz"`, jobName, jobID, extension))
+		} else {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(http.StatusOK)
+
+		buffered = bufio.NewWriter(w)
+		out = buffered
+		if compressed {
+			gz = gzip.NewWriter(buffered)
+			out = gz
+		}
+	}
+
+	write := func(entry models.LogEntry) error {
+		if out == nil {
+			start()
+		}
+		if format == logFormatNDJSON {
+			return json.NewEncoder(out).Encode(entry)
+		}
+		_, err := fmt.Fprintf(out, "%s [%s] %s %s\n", entry.Timestamp, entry.Container, entry.Level, entry.Message)
+		return err
+	}
+
+	exists, err := h.logService.StreamJobLogs(jobID, query, write)
+	if !exists {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Job not found",
+		})
+		return
+	}
+
+	// 조건에 맞는 로그가 없어도 빈 본문(또는 빈 gzip 스트림)으로 응답
+	if out == nil {
+		start()
+	}
+	if gz != nil {
+		gz.Close()
+	}
+	buffered.Flush()
+
+	if err != nil {
+		log.Printf("log download of %s interrupted: %v", jobID, err)
+	}
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"api-server/pkg/models"
+	"api-server/pkg/storage"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultListLimit는 limit 파라미터가 없을 때 한 페이지의 Job 수입니다
+	defaultListLimit = 50
+	// maxListLimit는 한 페이지에서 조회할 수 있는 최대 Job 수입니다
+	maxListLimit = 200
+)
+
+// List는 GET /api/buildjob 요청을 처리합니다
+//
+// 쿼리 파라미터:
+//   - status: 상태 필터 (쉼표로 여러 개 지정 가능)
+//   - name_prefix: Job 이름 접두사 필터
+//   - created_after, created_before: 생성 시각 범위 (RFC3339)
+//   - sort: created_at, -created_at(기본값), name, -name
+//   - limit: 페이지 크기 (기본 50, 최대 200)
+//   - cursor: 이전 응답의 next_cursor
+func (h *BuildJobHandler) List(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Only GET method is allowed",
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	query, err := parseJobQuery(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	jobs, nextCursor, err := h.jobService.List(query)
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Invalid cursor",
+		})
+		return
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Failed to list jobs: " + err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	response := models.JobListResponse{
+		Jobs:       make([]models.JobDetail, 0, len(jobs)),
+		NextCursor: nextCursor,
+	}
+	for _, job := range jobs {
+		state, _ := h.logService.GetJobStatus(job.ID)
+		response.Jobs = append(response.Jobs, jobDetail(job, state, now))
+	}
+	response.Count = len(response.Jobs)
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseJobQuery는 목록 조회 쿼리 파라미터를 JobQuery로 변환합니다
+func parseJobQuery(values url.Values) (storage.JobQuery, error) {
+	query := storage.JobQuery{
+		NamePrefix: values.Get("name_prefix"),
+		SortBy:     storage.SortByCreatedAt,
+		Descending: true,
+		Cursor:     values.Get("cursor"),
+		Limit:      defaultListLimit,
+	}
+
+	for _, value := range values["status"] {
+		for _, status := range strings.Split(value, ",") {
+			status = strings.TrimSpace(status)
+			if !models.JobStatus(status).IsValid() {
+				return query, fmt.Errorf("invalid status %q", status)
+			}
+			query.Statuses = append(query.Statuses, models.JobStatus(status))
+		}
+	}
+
+	for name, target := range map[string]*time.Time{
+		"created_after":  &query.CreatedAfter,
+		"created_before": &query.CreatedBefore,
+	} {
+		value := values.Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, fmt.Errorf("invalid %s: must be RFC3339", name)
+		}
+		*target = t
+	}
+
+	if sort := values.Get("sort"); sort != "" {
+		query.Descending = strings.HasPrefix(sort, "-")
+		query.SortBy = strings.TrimPrefix(sort, "-")
+		if query.SortBy != storage.SortByCreatedAt && query.SortBy != storage.SortByName {
+			return query, fmt.Errorf("invalid sort %q", sort)
+		}
+	}
+
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			return query, fmt.Errorf("invalid limit: must be between 1 and %d", maxListLimit)
+		}
+		query.Limit = limit
+	}
+
+	return query, nil
+}
+
+// jobDetail은 Job과 현재 상태로 응답을 만듭니다
+// 소요 시간은 생성 시각부터 종료 시각(종료되지 않았으면 now)까지입니다
+func jobDetail(job models.Job, state models.JobState, now time.Time) models.JobDetail {
+	detail := models.JobDetail{
+		JobID:     job.ID,
+		JobName:   job.Name,
+		ImageName: job.ImageName,
+		Namespace: job.Namespace,
+		Status:    state.Status,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: state.UpdatedAt,
+		GitURL:    job.GitURL,
+		GitRef:    job.GitRef,
+		GitCommit: job.GitCommit,
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, job.CreatedAt)
+	if err != nil {
+		return detail
+	}
+	end := now
+	if state.Status.IsTerminal() {
+		if finishedAt, err := time.Parse(time.RFC3339, state.UpdatedAt); err == nil {
+			end = finishedAt
+		}
+	}
+	if end.After(createdAt) {
+		detail.DurationSeconds = int64(end.Sub(createdAt).Seconds())
+	}
+	return detail
+}
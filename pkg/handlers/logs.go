@@ -3,24 +3,52 @@ package handlers
 import (
 	"api-server/pkg/models"
 	"api-server/pkg/services"
+	"api-server/pkg/storage"
+	"api-server/pkg/utils"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // LogsHandler는 로그 조회 핸들러입니다
 type LogsHandler struct {
 	logService services.LogService
+	jobService services.JobService
 }
 
 // NewLogsHandler는 새로운 LogsHandler를 생성합니다
-func NewLogsHandler(logService services.LogService) *LogsHandler {
+func NewLogsHandler(logService services.LogService, jobService services.JobService) *LogsHandler {
 	return &LogsHandler{
 		logService: logService,
+		jobService: jobService,
 	}
 }
 
-// Get은 GET /api/buildjob/{job_name}/logs를 처리합니다
+const (
+	// maxLogLimit는 한 번에 조회할 수 있는 최대 로그 수입니다
+	maxLogLimit = 10000
+	// maxLogPatternLength는 pattern 파라미터의 최대 길이입니다
+	maxLogPatternLength = 256
+)
+
+// Get은 GET /api/buildjob/{job_id}/logs를 처리합니다
+// follow=true이면 Server-Sent Events로 새 로그를 계속 전송합니다 (limit와 format은 무시)
+//
+// 쿼리 파라미터:
+//   - since_seq: 이 Seq 이후의 로그만 조회 (이전 응답의 next_seq)
+//   - tail: 마지막 N개만 조회
+//   - limit: 최대 N개까지 조회 (최대 10000), 남은 로그가 있으면 has_more가 true
+//   - container: 컨테이너 필터 (prepare, buildkit, system, 쉼표로 여러 개 지정 가능)
+//   - level: 최소 로그 레벨 (info, warn, error)
+//   - contains: 메시지 부분 문자열 필터
+//   - pattern: 메시지 정규식 필터 (RE2 문법)
+//   - since, until: 로그 시각 범위 (RFC3339)
+//   - format: json(기본값), text, ndjson, text.gz, ndjson.gz (Accept 헤더로도 지정 가능)
 func (h *LogsHandler) Get(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		w.Header().Set("Content-Type", "application/json")
@@ -31,12 +59,36 @@ func (h *LogsHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jobName := strings.TrimPrefix(r.URL.Path, "/api/buildjob/")
-	jobName = strings.TrimSuffix(jobName, "/logs")
+	jobID, jobName := resolveJob(h.jobService, jobKeyFromPath(r.URL.Path, "/logs"))
 
 	w.Header().Set("Content-Type", "application/json")
 
-	logs, exists := h.logService.GetJobLogs(jobName)
+	query, err := parseLogQuery(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	if r.URL.Query().Get("follow") == "true" {
+		h.follow(w, r, jobID, jobName, query)
+		return
+	}
+
+	if format := negotiateLogFormat(r); format != logFormatJSON {
+		h.download(w, jobID, jobName, query, format)
+		return
+	}
+
+	// 남은 로그가 있는지 확인하기 위해 하나 더 조회
+	limit := query.Limit
+	if limit > 0 {
+		query.Limit++
+	}
+
+	logs, exists := h.logService.QueryJobLogs(jobID, query)
 	if !exists {
 		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(models.ErrorResponse{
@@ -45,13 +97,101 @@ func (h *LogsHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	hasMore := limit > 0 && len(logs) > limit
+	if hasMore {
+		logs = logs[:limit]
+	}
+
+	nextSeq := query.SinceSeq
+	if len(logs) > 0 {
+		nextSeq = logs[len(logs)-1].Seq
+	}
+
+	state, _ := h.logService.GetJobStatus(jobID)
+
 	response := models.LogsResponse{
+		JobID:      jobID,
 		JobName:    jobName,
-		Status:     "running",
+		Status:     state.Status,
 		Logs:       logs,
 		TotalLines: len(logs),
+		NextSeq:    nextSeq,
+		HasMore:    hasMore,
 	}
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// parseLogQuery는 로그 조회 쿼리 파라미터를 LogQuery로 변환합니다
+func parseLogQuery(values url.Values) (storage.LogQuery, error) {
+	var query storage.LogQuery
+
+	if value := values.Get("since_seq"); value != "" {
+		since, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || since < 0 {
+			return query, fmt.Errorf("invalid since_seq: must be a non-negative integer")
+		}
+		query.SinceSeq = since
+	}
+
+	for name, target := range map[string]*int{
+		"limit": &query.Limit,
+		"tail":  &query.Tail,
+	} {
+		value := values.Get(name)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxLogLimit {
+			return query, fmt.Errorf("invalid %s: must be between 1 and %d", name, maxLogLimit)
+		}
+		*target = n
+	}
+
+	for _, value := range values["container"] {
+		for _, container := range strings.Split(value, ",") {
+			if container = strings.TrimSpace(container); container != "" {
+				query.Containers = append(query.Containers, container)
+			}
+		}
+	}
+
+	if level := values.Get("level"); level != "" {
+		if utils.LogLevelSeverity(level) < 0 {
+			return query, fmt.Errorf("invalid level %q: must be one of info, warn, error", level)
+		}
+		query.MinLevel = level
+	}
+
+	query.Contains = values.Get("contains")
+
+	if pattern := values.Get("pattern"); pattern != "" {
+		if len(pattern) > maxLogPatternLength {
+			return query, fmt.Errorf("invalid pattern: must be at most %d characters", maxLogPatternLength)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return query, fmt.Errorf("invalid pattern: %v", err)
+		}
+		query.Pattern = re
+	}
+
+	for name, target := range map[string]*time.Time{
+		"since": &query.Since,
+		"until": &query.Until,
+	} {
+		value := values.Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, fmt.Errorf("invalid %s: must be RFC3339", name)
+		}
+		*target = t
+	}
+
+	return query, nil
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"api-server/pkg/models"
+	"api-server/pkg/services"
+	"strings"
+)
+
+// jobKeyFromPath는 /api/buildjob/{job}{suffix} 경로에서 Job ID 또는 이름을 추출합니다
+func jobKeyFromPath(path, suffix string) string {
+	key := strings.TrimPrefix(path, "/api/buildjob/")
+	return strings.TrimSuffix(key, suffix)
+}
+
+// findJob은 Job ID로 조회하고, 없으면 같은 이름의 가장 최근 Job을 조회합니다
+func findJob(jobService services.JobService, key string) (models.Job, bool) {
+	if job, exists := jobService.GetJob(key); exists {
+		return job, true
+	}
+	return jobService.FindByName(key)
+}
+
+// resolveJob은 경로의 Job ID 또는 이름을 로그/상태 저장 키(Job ID)와 Job 이름으로 변환합니다
+// 등록되지 않은 키는 그대로 사용합니다
+func resolveJob(jobService services.JobService, key string) (jobID, jobName string) {
+	if job, exists := findJob(jobService, key); exists {
+		return job.ID, job.Name
+	}
+	return key, key
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"api-server/pkg/models"
+	"api-server/pkg/storage"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// sseKeepAliveInterval은 프록시가 유휴 연결을 끊지 않도록 주석 이벤트를 보내는 주기입니다
+	sseKeepAliveInterval = 15 * time.Second
+	// sseBatchSize는 한 번에 저장소에서 읽어 전송하는 최대 로그 수입니다
+	sseBatchSize = 100
+)
+
+// follow는 Job 로그를 Server-Sent Events로 스트리밍합니다
+//
+// 각 로그는 id가 Seq인 log 이벤트로 전송되며, 재연결 시 Last-Event-ID 헤더의 Seq 이후 로그부터,
+// Last-Event-ID가 없으면 since_seq 쿼리 파라미터의 Seq 이후 로그부터 이어서 전송합니다
+// container, level, contains, pattern, since, until 필터는 조회와 같이 적용되고,
+// tail은 처음 전송할 로그 수에만 적용됩니다
+// Job이 종료 상태가 되면 상태를 담은 end 이벤트를 보내고 연결을 닫습니다
+func (h *LogsHandler) follow(w http.ResponseWriter, r *http.Request, jobID, jobName string, query storage.LogQuery) {
+	// 구독 이후의 변경을 놓치지 않도록 조회 전에 먼저 구독
+	notify, unsubscribe := h.logService.Subscribe(jobID)
+	defer unsubscribe()
+
+	if !h.logService.JobExists(jobID) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Job not found",
+		})
+		return
+	}
+
+	if lastEventID, err := strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64); err == nil && lastEventID >= 0 {
+		query.SinceSeq = lastEventID
+		query.Tail = 0
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	rc := http.NewResponseController(w)
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	// tail을 지정하면 처음 한 번만 마지막 N개를 보내고, 이후에는 배치 단위로 이어서 전송
+	query.Limit = 0
+	if query.Tail == 0 {
+		query.Limit = sseBatchSize
+	}
+
+	for {
+		// 상태를 먼저 확인하여 종료 시점까지의 로그를 모두 보낸 뒤 end 이벤트를 전송
+		state, _ := h.logService.GetJobStatus(jobID)
+		logs, exists := h.logService.QueryJobLogs(jobID, query)
+
+		for _, entry := range logs {
+			data, _ := json.Marshal(entry)
+			fmt.Fprintf(w, "id: %d\nevent: log\ndata: %s\n\n", entry.Seq, data)
+			query.SinceSeq = entry.Seq
+		}
+
+		// 배치 크기만큼 보냈으면 남은 로그가 있을 수 있으므로 대기하지 않고 다시 읽음
+		hasMore := query.Limit > 0 && len(logs) == query.Limit
+		query.Tail, query.Limit = 0, sseBatchSize
+		if hasMore {
+			if err := rc.Flush(); err != nil {
+				return
+			}
+			continue
+		}
+
+		if !exists || state.Status.IsTerminal() {
+			data, _ := json.Marshal(models.JobStatusResponse{
+				JobID:     jobID,
+				JobName:   jobName,
+				Status:    state.Status,
+				UpdatedAt: state.UpdatedAt,
+			})
+			fmt.Fprintf(w, "event: end\ndata: %s\n\n", data)
+			rc.Flush()
+			return
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-notify:
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+	}
+}
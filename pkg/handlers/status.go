@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"api-server/pkg/models"
+	"api-server/pkg/services"
+	"encoding/json"
+	"net/http"
+)
+
+// StatusHandler는 Job 상태 조회 핸들러입니다
+type StatusHandler struct {
+	logService services.LogService
+	jobService services.JobService
+}
+
+// NewStatusHandler는 새로운 StatusHandler를 생성합니다
+func NewStatusHandler(logService services.LogService, jobService services.JobService) *StatusHandler {
+	return &StatusHandler{
+		logService: logService,
+		jobService: jobService,
+	}
+}
+
+// Get은 GET /api/buildjob/{job_id}/status를 처리합니다
+func (h *StatusHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Only GET method is allowed",
+		})
+		return
+	}
+
+	jobID, jobName := resolveJob(h.jobService, jobKeyFromPath(r.URL.Path, "/status"))
+
+	w.Header().Set("Content-Type", "application/json")
+
+	state, exists := h.logService.GetJobStatus(jobID)
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Job not found",
+		})
+		return
+	}
+
+	response := models.JobStatusResponse{
+		JobID:     jobID,
+		JobName:   jobName,
+		Status:    state.Status,
+		UpdatedAt: state.UpdatedAt,
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
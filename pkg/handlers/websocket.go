@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"api-server/pkg/models"
+	"api-server/pkg/storage"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// wsWriteTimeout은 한 메시지를 전송할 수 있는 최대 시간입니다
+	// 이 시간 안에 받지 못하는 느린 클라이언트는 연결을 끊고 since_seq로 재연결하게 합니다
+	wsWriteTimeout = 10 * time.Second
+	// wsPongTimeout은 pong 응답을 기다리는 최대 시간입니다
+	wsPongTimeout = 60 * time.Second
+	// wsPingInterval은 ping 전송 주기입니다
+	wsPingInterval = wsPongTimeout * 9 / 10
+	// wsBatchSize는 한 번에 저장소에서 읽어 전송하는 최대 로그 수입니다
+	wsBatchSize = 100
+)
+
+// upgrader는 WebSocket 업그레이드 설정입니다 (브라우저 요청은 같은 origin만 허용)
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+}
+
+// WebSocket은 GET /api/buildjob/{job_id}/logs/ws를 처리합니다
+//
+// 로그를 LogStreamMessage 프레임으로 전송하며, since_seq 쿼리 파라미터로 전달한
+// Seq 이후의 로그부터 이어서 전송합니다. Job이 종료 상태가 되면 end 메시지를 보내고 닫습니다
+//
+// 전송할 로그는 클라이언트별로 쌓아두지 않고 전송할 때마다 저장소에서 읽으므로
+// 느린 클라이언트 때문에 서버 메모리가 늘어나지 않습니다
+func (h *LogsHandler) WebSocket(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Only GET method is allowed",
+		})
+		return
+	}
+
+	jobID, jobName := resolveJob(h.jobService, jobKeyFromPath(r.URL.Path, "/logs/ws"))
+
+	var since int64
+	if value := r.URL.Query().Get("since_seq"); value != "" {
+		var err error
+		if since, err = strconv.ParseInt(value, 10, 64); err != nil || since < 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(models.ErrorResponse{
+				Error: "invalid since_seq: must be a non-negative integer",
+			})
+			return
+		}
+	}
+
+	// 구독 이후의 변경을 놓치지 않도록 조회 전에 먼저 구독
+	notify, unsubscribe := h.logService.Subscribe(jobID)
+	defer unsubscribe()
+
+	if !h.logService.JobExists(jobID) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(models.ErrorResponse{
+			Error: "Job not found",
+		})
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade가 이미 에러 응답을 보냄
+		return
+	}
+	defer conn.Close()
+
+	closed := readControlFrames(conn)
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	write := func(message models.LogStreamMessage) error {
+		conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		return conn.WriteJSON(message)
+	}
+
+	for {
+		state, _ := h.logService.GetJobStatus(jobID)
+		logs, exists := h.logService.QueryJobLogs(jobID, storage.LogQuery{SinceSeq: since, Limit: wsBatchSize})
+
+		for i := range logs {
+			if err := write(models.LogStreamMessage{Type: "log", Log: &logs[i]}); err != nil {
+				return
+			}
+			since = logs[i].Seq
+		}
+
+		// 배치 크기만큼 보냈으면 남은 로그가 있을 수 있으므로 대기하지 않고 다시 읽음
+		if len(logs) == wsBatchSize {
+			continue
+		}
+
+		if !exists || state.Status.IsTerminal() {
+			write(models.LogStreamMessage{Type: "end", Status: &models.JobStatusResponse{
+				JobID:     jobID,
+				JobName:   jobName,
+				Status:    state.Status,
+				UpdatedAt: state.UpdatedAt,
+			}})
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, "job finished"),
+				time.Now().Add(wsWriteTimeout))
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-closed:
+			return
+		case <-notify:
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readControlFrames는 클라이언트가 보내는 ping/pong/close 프레임을 처리하고
+// 연결이 끊기면 닫히는 채널을 반환합니다 (클라이언트 메시지는 무시)
+func readControlFrames(conn *websocket.Conn) <-chan struct{} {
+	closed := make(chan struct{})
+
+	conn.SetReadLimit(512)
+	conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+	return closed
+}
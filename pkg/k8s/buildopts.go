@@ -0,0 +1,122 @@
+package k8s
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"api-server/pkg/models"
+)
+
+const (
+	// maxBuildOptions는 build_args, labels 각각에 지정할 수 있는 최대 항목 수입니다
+	maxBuildOptions = 100
+	// maxBuildOptionValueLength는 build_args, labels 값의 최대 길이입니다
+	maxBuildOptionValueLength = 4096
+	// maxPlatforms는 한 번에 빌드할 수 있는 최대 플랫폼 수입니다
+	maxPlatforms = 8
+)
+
+var (
+	// buildArgKeyPattern은 Dockerfile ARG 이름 패턴입니다
+	buildArgKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,127}$`)
+	// labelKeyPattern은 이미지 라벨 키 패턴입니다 (예: org.opencontainers.image.source)
+	labelKeyPattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9._/-]{0,253}[A-Za-z0-9])?$`)
+	// targetPattern은 Dockerfile 빌드 스테이지 이름 패턴입니다
+	targetPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]{0,127}$`)
+	// platformPattern은 os/arch[/variant] 형식의 플랫폼 패턴입니다
+	platformPattern = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
+)
+
+// ValidateBuildOptions는 build_args, target, platforms, labels가 buildctl --opt로 안전하게 전달될 수 있는지 검사하고
+// 위반한 규칙 목록을 반환합니다 (위반이 없으면 빈 슬라이스)
+func ValidateBuildOptions(req models.BuildJobRequest) []string {
+	var violations []string
+
+	if len(req.BuildArgs) > maxBuildOptions {
+		violations = append(violations, fmt.Sprintf("build_args must not have more than %d entries", maxBuildOptions))
+	}
+	for _, key := range sortedKeys(req.BuildArgs) {
+		if !buildArgKeyPattern.MatchString(key) {
+			violations = append(violations, fmt.Sprintf("build_args key %q must be a valid ARG name", key))
+		}
+		if err := validateOptionValue(req.BuildArgs[key]); err != "" {
+			violations = append(violations, fmt.Sprintf("build_args value of %q %s", key, err))
+		}
+	}
+
+	if len(req.Labels) > maxBuildOptions {
+		violations = append(violations, fmt.Sprintf("labels must not have more than %d entries", maxBuildOptions))
+	}
+	for _, key := range sortedKeys(req.Labels) {
+		if !labelKeyPattern.MatchString(key) {
+			violations = append(violations, fmt.Sprintf("labels key %q must consist of alphanumerics, '.', '_', '-' or '/'", key))
+		}
+		if err := validateOptionValue(req.Labels[key]); err != "" {
+			violations = append(violations, fmt.Sprintf("labels value of %q %s", key, err))
+		}
+	}
+
+	if req.Target != "" && !targetPattern.MatchString(req.Target) {
+		violations = append(violations, fmt.Sprintf("target %q must be a valid build stage name", req.Target))
+	}
+
+	if len(req.Platforms) > maxPlatforms {
+		violations = append(violations, fmt.Sprintf("platforms must not have more than %d entries", maxPlatforms))
+	}
+	for i, platform := range req.Platforms {
+		if !platformPattern.MatchString(platform) {
+			violations = append(violations, fmt.Sprintf("platform %q must be in os/arch[/variant] form (e.g. linux/arm64)", platform))
+		} else if slices.Contains(req.Platforms[:i], platform) {
+			violations = append(violations, fmt.Sprintf("platform %q is listed more than once", platform))
+		}
+	}
+
+	return violations
+}
+
+// validateOptionValue는 --opt 값에 허용되지 않는 내용이 있으면 이유를 반환합니다
+func validateOptionValue(value string) string {
+	if len(value) > maxBuildOptionValueLength {
+		return fmt.Sprintf("must be at most %d bytes", maxBuildOptionValueLength)
+	}
+	if strings.ContainsFunc(value, func(r rune) bool { return r < 0x20 || r == 0x7f }) {
+		return "must not contain control characters"
+	}
+	return ""
+}
+
+// frontendOpts는 요청의 빌드 옵션을 dockerfile 프런트엔드의 --opt 인자로 변환합니다
+// 같은 요청에서 항상 같은 인자가 생성되도록 키 순서로 정렬합니다
+func frontendOpts(req models.BuildJobRequest) []string {
+	var args []string
+	if req.Target != "" {
+		args = append(args, "--opt", "target="+req.Target)
+	}
+	if len(req.Platforms) > 0 {
+		args = append(args, "--opt", "platform="+strings.Join(req.Platforms, ","))
+	}
+	for _, key := range sortedKeys(req.BuildArgs) {
+		args = append(args, "--opt", "build-arg:"+key+"="+req.BuildArgs[key])
+	}
+	for _, key := range sortedKeys(req.Labels) {
+		args = append(args, "--opt", "label:"+key+"="+req.Labels[key])
+	}
+	if req.NoCache {
+		// 값이 비어 있으면 모든 스테이지의 캐시를 사용하지 않음
+		args = append(args, "--opt", "no-cache=")
+	}
+	return args
+}
+
+// sortedKeys는 map의 키를 정렬하여 반환합니다
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
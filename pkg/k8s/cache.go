@@ -0,0 +1,206 @@
+package k8s
+
+import (
+	"fmt"
+	"regexp"
+
+	"api-server/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// 빌드 캐시 종류
+const (
+	CacheNone     = "none"
+	CacheRegistry = "registry"
+	CacheLocal    = "local"
+	CacheInline   = "inline"
+)
+
+// 내보낼 캐시 범위
+const (
+	CacheModeMin = "min"
+	CacheModeMax = "max"
+)
+
+const (
+	// DefaultCacheMode는 캐시 범위를 지정하지 않았을 때 사용하는 값입니다
+	DefaultCacheMode = CacheModeMax
+
+	// cacheTag는 registry 캐시 이미지의 태그입니다
+	cacheTag = "buildcache"
+	// cacheDir은 buildkit 컨테이너에 local 캐시 PVC를 마운트하는 경로입니다
+	cacheDir = "/cache"
+)
+
+// cacheScopePattern은 캐시 scope 패턴입니다 (registry 저장소 이름과 PVC 하위 디렉터리로 사용)
+var cacheScopePattern = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*$`)
+
+// CachePolicy는 요청에 cache가 없을 때 적용하는 서버 기본 캐시 정책입니다
+type CachePolicy struct {
+	// Type은 기본 캐시 종류입니다 (빈 값은 none)
+	Type string
+	// Mode는 기본 캐시 범위입니다 (빈 값은 DefaultCacheMode)
+	Mode string
+	// Registry는 registry 캐시 이미지를 저장할 저장소 접두사입니다 (예: registry.example.com/cache)
+	Registry string
+	// ClaimName은 local 캐시를 담는 PVC 이름입니다 (비어 있으면 local 캐시 사용 불가)
+	ClaimName string
+}
+
+// Validate는 서버 캐시 정책이 올바른지 확인합니다
+func (p CachePolicy) Validate() error {
+	switch p.Type {
+	case "", CacheNone, CacheInline:
+	case CacheRegistry:
+		if p.Registry == "" {
+			return fmt.Errorf("a cache registry is required for the registry cache policy")
+		}
+	case CacheLocal:
+		if p.ClaimName == "" {
+			return fmt.Errorf("a cache PVC is required for the local cache policy")
+		}
+	default:
+		return fmt.Errorf("unknown cache type %q: must be none, registry, local or inline", p.Type)
+	}
+
+	if p.Mode != "" && p.Mode != CacheModeMin && p.Mode != CacheModeMax {
+		return fmt.Errorf("unknown cache mode %q: must be min or max", p.Mode)
+	}
+	if p.Registry != "" && !imageReferencePattern.MatchString(p.Registry+"/scope") {
+		return fmt.Errorf("invalid cache registry %q", p.Registry)
+	}
+	if p.ClaimName != "" && len(validation.IsDNS1123Subdomain(p.ClaimName)) > 0 {
+		return fmt.Errorf("invalid cache PVC name %q", p.ClaimName)
+	}
+	return nil
+}
+
+// ResolveCache는 요청의 cache 설정에 서버 기본 정책을 적용하여 빌드에 사용할 캐시를 결정합니다
+// scope를 지정하지 않으면 defaultScope(보통 Job 이름)를 사용하며, 캐시를 사용하지 않으면 nil을 반환합니다
+func ResolveCache(req models.BuildJobRequest, defaultScope string, policy CachePolicy) (*models.BuildCache, error) {
+	explicit := req.Cache != nil
+	cache := models.BuildCache{Type: policy.Type}
+	if explicit {
+		cache = *req.Cache
+	}
+	if cache.Type == "" || cache.Type == CacheNone {
+		return nil, nil
+	}
+
+	if cache.Scope == "" {
+		cache.Scope = defaultScope
+	} else if !cacheScopePattern.MatchString(cache.Scope) || len(cache.Scope) > validation.DNS1123LabelMaxLength {
+		return nil, fmt.Errorf("invalid cache scope %q: must consist of lowercase alphanumerics separated by '.', '_' or '-'", cache.Scope)
+	}
+
+	// 인라인 캐시는 push된 이미지에 함께 저장되므로 push하지 않는 빌드에는 의미가 없음
+	if cache.Type == CacheInline {
+		if cache.Ref != "" || cache.Mode != "" {
+			return nil, fmt.Errorf("inline cache does not support ref or mode")
+		}
+		if !req.PushRegistry {
+			if explicit {
+				return nil, fmt.Errorf("inline cache requires push_registry")
+			}
+			return nil, nil
+		}
+		return &cache, nil
+	}
+
+	if cache.Mode == "" {
+		cache.Mode = policy.Mode
+		if cache.Mode == "" {
+			cache.Mode = DefaultCacheMode
+		}
+	}
+	if cache.Mode != CacheModeMin && cache.Mode != CacheModeMax {
+		return nil, fmt.Errorf("invalid cache mode %q: must be min or max", cache.Mode)
+	}
+
+	switch cache.Type {
+	case CacheRegistry:
+		if cache.Ref == "" {
+			if policy.Registry == "" {
+				return nil, fmt.Errorf("cache ref is required because no cache registry is configured")
+			}
+			cache.Ref = policy.Registry + "/" + cache.Scope + ":" + cacheTag
+		}
+		if !imageReferencePattern.MatchString(cache.Ref) {
+			return nil, fmt.Errorf("invalid cache ref %q", cache.Ref)
+		}
+	case CacheLocal:
+		if policy.ClaimName == "" {
+			return nil, fmt.Errorf("local cache is not configured on this server")
+		}
+		if cache.Ref != "" {
+			return nil, fmt.Errorf("cache ref is only supported for registry cache")
+		}
+		cache.ClaimName = policy.ClaimName
+	default:
+		return nil, fmt.Errorf("invalid cache type %q: must be none, registry, local or inline", cache.Type)
+	}
+	return &cache, nil
+}
+
+// cacheArgs는 캐시 설정을 buildctl --import-cache, --export-cache 인자로 변환합니다
+// local 캐시 디렉터리가 아직 없으면 buildctl은 가져오기를 건너뜁니다
+func cacheArgs(cache *models.BuildCache, imageReference string) []string {
+	if cache == nil {
+		return nil
+	}
+
+	switch cache.Type {
+	case CacheRegistry:
+		return []string{
+			"--import-cache", "type=registry,ref=" + cache.Ref,
+			"--export-cache", "type=registry,ref=" + cache.Ref + ",mode=" + cache.Mode,
+		}
+	case CacheLocal:
+		dir := cacheDir + "/" + cache.Scope
+		return []string{
+			"--import-cache", "type=local,src=" + dir,
+			"--export-cache", "type=local,dest=" + dir + ",mode=" + cache.Mode,
+		}
+	case CacheInline:
+		return []string{
+			"--import-cache", "type=registry,ref=" + imageReference,
+			"--export-cache", "type=inline",
+		}
+	}
+	return nil
+}
+
+// mountCache는 local 캐시 PVC를 buildkit 컨테이너에 마운트합니다
+// rootless buildkit(UID 1000)이 쓸 수 있도록 fsGroup을 지정합니다
+func mountCache(podSpec *corev1.PodSpec, cache *models.BuildCache) {
+	if cache == nil || cache.Type != CacheLocal {
+		return
+	}
+
+	podSpec.SecurityContext = &corev1.PodSecurityContext{FSGroup: int64Ptr(1000)}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "build-cache",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: cache.ClaimName},
+		},
+	})
+
+	buildkit := &podSpec.Containers[0]
+	buildkit.VolumeMounts = append(buildkit.VolumeMounts, corev1.VolumeMount{
+		Name:      "build-cache",
+		MountPath: cacheDir,
+	})
+}
+
+// CacheDescription은 로그에 남길 캐시 설정 설명입니다
+func CacheDescription(cache *models.BuildCache) string {
+	switch cache.Type {
+	case CacheRegistry:
+		return fmt.Sprintf("registry cache %s (mode=%s)", cache.Ref, cache.Mode)
+	case CacheLocal:
+		return fmt.Sprintf("local cache %s:%s (mode=%s)", cache.ClaimName, cache.Scope, cache.Mode)
+	}
+	return cache.Type + " cache"
+}
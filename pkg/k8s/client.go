@@ -0,0 +1,38 @@
+package k8s
+
+import (
+	"os"
+	"path/filepath"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// NewClientset은 Kubernetes 클라이언트를 생성합니다
+// 클러스터 내부에서는 ServiceAccount 설정을 사용하고,
+// 그 외(로컬 KIND 등)에서는 KUBECONFIG 또는 ~/.kube/config로 fallback 합니다
+func NewClientset() (kubernetes.Interface, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return kubernetes.NewForConfig(config)
+}
+
+// kubeconfigPath는 사용할 kubeconfig 파일 경로를 반환합니다
+func kubeconfigPath() string {
+	if path := os.Getenv("KUBECONFIG"); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".kube", "config")
+}
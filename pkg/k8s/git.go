@@ -0,0 +1,183 @@
+package k8s
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"api-server/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// gitImage는 Git 저장소를 클론하는 prepare 컨테이너 이미지입니다
+	gitImage = "alpine/git:latest"
+	// gitCheckoutDir은 workspace에서 저장소를 클론하는 경로입니다
+	gitCheckoutDir = workspaceDir + "/src"
+)
+
+var (
+	// gitRefPattern은 브랜치, 태그, 커밋 이름으로 허용하는 문자 패턴입니다
+	// '-'로 시작하는 값은 git 옵션으로 해석될 수 있으므로 허용하지 않습니다
+	gitRefPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9._/+-]{0,254}$`)
+	// scpLikeGitURLPattern은 git@github.com:org/repo.git 형식의 SSH 주소 패턴입니다
+	scpLikeGitURLPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9._-]*@[A-Za-z0-9][A-Za-z0-9.-]*:[A-Za-z0-9_~][A-Za-z0-9._~/-]*$`)
+	// gitCommitPattern은 SHA-1 또는 SHA-256 커밋 해시 패턴입니다
+	gitCommitPattern = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+)
+
+// ValidateGitSource는 Git 저장소 주소, ref, 경로를 검증합니다
+// 인증 정보가 포함된 주소는 매니페스트와 로그에 그대로 남으므로 허용하지 않습니다
+func ValidateGitSource(git models.GitSource) error {
+	if err := validateGitURL(git.URL); err != nil {
+		return err
+	}
+
+	if git.Ref != "" && (!gitRefPattern.MatchString(git.Ref) || strings.Contains(git.Ref, "..") ||
+		strings.HasSuffix(git.Ref, "/") || strings.HasSuffix(git.Ref, ".lock")) {
+		return fmt.Errorf("invalid git ref %q", git.Ref)
+	}
+
+	if git.ContextDir != "" {
+		if _, ok := repositoryPath(git.ContextDir); !ok {
+			return fmt.Errorf("invalid git context_dir %q: must be a relative path inside the repository", git.ContextDir)
+		}
+	}
+	if git.Dockerfile != "" {
+		if name, ok := repositoryPath(git.Dockerfile); !ok || name == "." {
+			return fmt.Errorf("invalid git dockerfile %q: must be a relative path inside the context directory", git.Dockerfile)
+		}
+	}
+	return nil
+}
+
+// validateGitURL은 https://, http://, git:// 주소인지 확인합니다
+// prepare 컨테이너에는 SSH 키와 known_hosts가 없어 클론할 수 없으므로 ssh:// 및 scp 형식 주소는 허용하지 않습니다
+func validateGitURL(raw string) error {
+	if raw == "" {
+		return fmt.Errorf("git url is required")
+	}
+	if scpLikeGitURLPattern.MatchString(raw) {
+		return errSSHGitURL(raw)
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || strings.ContainsAny(raw, " \t\r\n") {
+		return fmt.Errorf("invalid git url %q", raw)
+	}
+	switch parsed.Scheme {
+	case "https", "http", "git":
+	case "ssh":
+		return errSSHGitURL(raw)
+	default:
+		return fmt.Errorf("invalid git url %q: unsupported scheme %q", raw, parsed.Scheme)
+	}
+	if parsed.User != nil {
+		return fmt.Errorf("invalid git url: credentials must not be included in the url")
+	}
+	return nil
+}
+
+// errSSHGitURL은 SSH 저장소 주소를 거부하는 에러입니다
+func errSSHGitURL(raw string) error {
+	return fmt.Errorf("invalid git url %q: ssh repositories are not supported, use an https:// url", raw)
+}
+
+// repositoryPath는 저장소 내 경로를 정리합니다
+// 절대 경로이거나 ..로 저장소 밖을 가리키면 false를 반환합니다
+func repositoryPath(name string) (string, bool) {
+	if path.IsAbs(name) || strings.Contains(name, `\`) {
+		return "", false
+	}
+	cleaned := path.Clean(name)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", false
+	}
+	return cleaned, true
+}
+
+// gitBuildSource는 클론한 저장소에서 빌드할 컨텍스트 디렉터리와 Dockerfile 위치를 반환합니다
+// dockerfile_content가 함께 지정되면 prepare 컨테이너가 workspace에 복사한 Dockerfile을 사용합니다
+func gitBuildSource(git models.GitSource, overrideDockerfile bool) buildSource {
+	contextDir := gitCheckoutDir
+	if dir, _ := repositoryPath(git.ContextDir); git.ContextDir != "" && dir != "." {
+		contextDir += "/" + dir
+	}
+	if overrideDockerfile {
+		return buildSource{contextDir: contextDir, dockerfileDir: workspaceDir, dockerfileName: DockerfileKey}
+	}
+
+	dockerfile := DockerfileKey
+	if git.Dockerfile != "" {
+		dockerfile, _ = repositoryPath(git.Dockerfile)
+	}
+	source := buildSource{contextDir: contextDir, dockerfileDir: contextDir, dockerfileName: path.Base(dockerfile)}
+	if dir := path.Dir(dockerfile); dir != "." {
+		source.dockerfileDir += "/" + dir
+	}
+	return source
+}
+
+// gitPrepareContainer는 Git 저장소를 workspace에 클론하는 init 컨테이너입니다
+// 체크아웃한 커밋은 로그와 컨테이너 종료 메시지로 남겨 StatusTracker가 Job 메타데이터에 기록합니다
+func gitPrepareContainer(git models.GitSource) corev1.Container {
+	return corev1.Container{
+		Name:    "prepare",
+		Image:   gitImage,
+		Command: []string{"sh", "-c", prepareGitScript},
+		// 요청 값은 스크립트에 넣지 않고 환경 변수로만 전달
+		Env: []corev1.EnvVar{
+			{Name: "GIT_URL", Value: git.URL},
+			{Name: "GIT_REF", Value: git.Ref},
+			{Name: "HOME", Value: "/tmp"},
+			{Name: "GIT_TERMINAL_PROMPT", Value: "0"},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:  int64Ptr(1000),
+			RunAsGroup: int64Ptr(1000),
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "dockerfile", ReadOnly: true, MountPath: dockerfileDir},
+			{Name: "workspace", MountPath: workspaceDir},
+		},
+	}
+}
+
+// prepareGitScript는 요청된 ref를 얕게 가져와 체크아웃하는 스크립트입니다
+// 커밋 해시처럼 얕은 fetch가 거부되는 ref는 전체 이력을 가져온 뒤 체크아웃합니다
+const prepareGitScript = `set -e
+git init -q ` + gitCheckoutDir + `
+cd ` + gitCheckoutDir + `
+git remote add origin "$GIT_URL"
+REF="${GIT_REF:-HEAD}"
+if git fetch -q --depth 1 origin "$REF"; then
+  git checkout -q FETCH_HEAD
+else
+  echo "Shallow fetch of $REF failed, fetching full history"
+  git fetch -q --tags origin
+  git checkout -q "$REF"
+fi
+if [ -f .gitmodules ]; then git submodule update -q --init --recursive --depth 1; fi
+COMMIT="$(git rev-parse HEAD)"
+rm -rf .git
+printf '%s' "$COMMIT" > /dev/termination-log
+echo "Checked out $GIT_URL $REF at commit $COMMIT"
+if [ -f ` + dockerfileDir + `/` + DockerfileKey + ` ]; then cp ` + dockerfileDir + `/` + DockerfileKey + ` ` + workspaceDir + `/` + DockerfileKey + `; fi`
+
+// GitCommit은 prepare 컨테이너가 종료 메시지로 남긴 체크아웃 커밋을 반환합니다
+// Git 저장소를 빌드하지 않았거나 아직 클론이 끝나지 않았으면 false를 반환합니다
+func GitCommit(pod *corev1.Pod) (string, bool) {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name != "prepare" || status.State.Terminated == nil || status.State.Terminated.ExitCode != 0 {
+			continue
+		}
+		commit := strings.TrimSpace(status.State.Terminated.Message)
+		if gitCommitPattern.MatchString(commit) {
+			return commit, true
+		}
+	}
+	return "", false
+}
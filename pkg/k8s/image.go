@@ -0,0 +1,48 @@
+package k8s
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// DefaultRegistrySecret는 push_registry 요청 시 마운트하는 기본 레지스트리 인증 Secret입니다
+	// (kubectl create secret docker-registry 로 생성한 dockerconfigjson 타입)
+	DefaultRegistrySecret = "registry-credentials"
+
+	// dockerConfigDir은 buildkit 컨테이너에서 레지스트리 인증 정보를 찾는 경로입니다
+	dockerConfigDir = "/home/user/.docker"
+)
+
+// imageReferencePattern은 [registry[:port]/]repository[:tag][@digest] 형식을 검증합니다
+var imageReferencePattern = regexp.MustCompile(
+	`^([a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?(:[0-9]+)?/)?` + // registry host
+		`[a-z0-9]+([._-]+[a-z0-9]+)*(/[a-z0-9]+([._-]+[a-z0-9]+)*)*` + // repository
+		`(:[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127})?` + // tag
+		`(@sha256:[a-f0-9]{64})?$`, // digest
+)
+
+// ImageReference는 빌드 결과 이미지의 전체 참조를 반환합니다
+// image_name이 없으면 Job 이름을, 태그가 없으면 latest 태그를 사용합니다
+func ImageReference(jobName, imageName string) (string, error) {
+	if imageName == "" {
+		return jobName + ":latest", nil
+	}
+
+	if !imageReferencePattern.MatchString(imageName) {
+		return "", fmt.Errorf("invalid image_name %q", imageName)
+	}
+
+	// 레지스트리 포트(host:5000/...)와 태그를 구분하기 위해 마지막 경로 요소만 검사
+	lastPart := imageName[strings.LastIndex(imageName, "/")+1:]
+	if !strings.Contains(lastPart, ":") && !strings.Contains(lastPart, "@") {
+		imageName += ":latest"
+	}
+	return imageName, nil
+}
+
+// OutputArg는 buildctl --output 인자를 생성합니다
+func OutputArg(imageReference string, push bool) string {
+	return fmt.Sprintf("type=image,name=%s,push=%t", imageReference, push)
+}
@@ -0,0 +1,152 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"api-server/pkg/models"
+
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ManagedByLabel은 api-server가 생성한 리소스를 구분하는 라벨입니다
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	// ManagedByValue는 ManagedByLabel의 값입니다
+	ManagedByValue = "api-server"
+	// JobNameLabel은 빌드 Job 이름을 담는 라벨입니다
+	JobNameLabel = "api-server/build-job"
+	// JobIDLabel은 빌드 Job ID를 담는 라벨입니다 (로그/상태 저장 키)
+	JobIDLabel = "api-server/job-id"
+)
+
+// JobClient는 빌드 Job을 Kubernetes API 서버에 제출합니다
+type JobClient struct {
+	clientset kubernetes.Interface
+	namespace string
+}
+
+// NewJobClient는 새로운 JobClient를 생성합니다
+func NewJobClient(clientset kubernetes.Interface, namespace string) *JobClient {
+	return &JobClient{
+		clientset: clientset,
+		namespace: namespace,
+	}
+}
+
+// Create는 Dockerfile ConfigMap, 컨텍스트 토큰 Secret과 BuildKit 빌드 Job을 생성합니다
+// ConfigMap과 Secret은 Job을 owner로 지정하여 Job 삭제 시 함께 정리되도록 합니다
+// Job이 생성된 뒤에는 owner 지정에 실패해도 빌드가 진행 중이므로 로그만 남기고 성공으로 처리합니다
+func (c *JobClient) Create(ctx context.Context, jobID string, req models.BuildJobRequest) error {
+	manifest, err := NewManifest(c.namespace, jobID, req)
+	if err != nil {
+		return err
+	}
+
+	configMaps := c.clientset.CoreV1().ConfigMaps(c.namespace)
+	if _, err := configMaps.Create(ctx, manifest.ConfigMap, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create configmap %s: %w", manifest.ConfigMap.Name, err)
+	}
+
+	secrets := c.clientset.CoreV1().Secrets(c.namespace)
+	if manifest.ContextSecret != nil {
+		if _, err := secrets.Create(ctx, manifest.ContextSecret, metav1.CreateOptions{}); err != nil {
+			configMaps.Delete(ctx, manifest.ConfigMap.Name, metav1.DeleteOptions{})
+			return fmt.Errorf("failed to create secret %s: %w", manifest.ContextSecret.Name, err)
+		}
+	}
+
+	job, err := c.createJob(ctx, manifest.Job)
+	if err != nil {
+		configMaps.Delete(ctx, manifest.ConfigMap.Name, metav1.DeleteOptions{})
+		if manifest.ContextSecret != nil {
+			secrets.Delete(ctx, manifest.ContextSecret.Name, metav1.DeleteOptions{})
+		}
+		return fmt.Errorf("failed to create job %s: %w", req.JobName, err)
+	}
+
+	owner := []metav1.OwnerReference{
+		*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind("Job")),
+	}
+	manifest.ConfigMap.OwnerReferences = owner
+	if _, err := configMaps.Update(ctx, manifest.ConfigMap, metav1.UpdateOptions{}); err != nil {
+		log.Printf("failed to set owner of configmap %s: %v", manifest.ConfigMap.Name, err)
+	}
+	if manifest.ContextSecret != nil {
+		manifest.ContextSecret.OwnerReferences = owner
+		if _, err := secrets.Update(ctx, manifest.ContextSecret, metav1.UpdateOptions{}); err != nil {
+			log.Printf("failed to set owner of secret %s: %v", manifest.ContextSecret.Name, err)
+		}
+	}
+	return nil
+}
+
+// createJob은 Job을 생성합니다
+// 같은 이름의 이전 빌드 Job이 TTL 정리 전에 남아 있으면 종료된 경우에 한해 삭제 후 다시 생성합니다
+func (c *JobClient) createJob(ctx context.Context, job *batchv1.Job) (*batchv1.Job, error) {
+	jobs := c.clientset.BatchV1().Jobs(c.namespace)
+
+	created, err := jobs.Create(ctx, job, metav1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		return created, err
+	}
+
+	previous, err := jobs.Get(ctx, job.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if _, finished := jobStatus(previous); !finished {
+		return nil, fmt.Errorf("job %s is still running", job.Name)
+	}
+
+	propagation := metav1.DeletePropagationBackground
+	if err := jobs.Delete(ctx, job.Name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	return jobs.Create(ctx, job, metav1.CreateOptions{})
+}
+
+// Delete는 빌드 Job을 foreground propagation으로 삭제합니다
+// 같은 이름의 다른 빌드를 삭제하지 않도록 Job ID 라벨이 일치하는 경우에만 삭제하며,
+// 이미 정리된 Job은 무시합니다
+func (c *JobClient) Delete(ctx context.Context, jobID, jobName string) error {
+	jobs := c.clientset.BatchV1().Jobs(c.namespace)
+
+	job, err := jobs.Get(ctx, jobName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get job %s: %w", jobName, err)
+	}
+	if job.Labels[JobIDLabel] != jobID {
+		return nil
+	}
+
+	propagation := metav1.DeletePropagationForeground
+	err = jobs.Delete(ctx, jobName, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+		Preconditions:     &metav1.Preconditions{UID: &job.UID},
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete job %s: %w", jobName, err)
+	}
+	return nil
+}
+
+// Helper 함수들
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,53 @@
+package k8s
+
+import (
+	"context"
+	"log"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// LeaderLeaseName은 로그 수집/상태 추적을 담당할 replica를 선출하는 Lease 이름입니다
+const LeaderLeaseName = "api-server-collector"
+
+// RunAsLeader는 ctx가 종료될 때까지 리더 선출에 참여하고, 리더인 동안에만 run을 실행합니다
+//
+// 여러 replica가 같은 저장소를 사용할 때 빌드 Pod 로그를 한 replica만 수집하도록 합니다
+// 리더를 잃으면 run의 ctx가 취소되고, 다시 선출되면 run을 새로 실행합니다
+func RunAsLeader(ctx context.Context, clientset kubernetes.Interface, namespace, identity string, run func(ctx context.Context)) {
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      LeaderLeaseName,
+			Namespace: namespace,
+		},
+		Client: clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	for ctx.Err() == nil {
+		leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			LeaseDuration:   15 * time.Second,
+			RenewDeadline:   10 * time.Second,
+			RetryPeriod:     2 * time.Second,
+			ReleaseOnCancel: true,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: run,
+				OnStoppedLeading: func() {
+					log.Printf("%s stopped leading %s", identity, LeaderLeaseName)
+				},
+				OnNewLeader: func(leader string) {
+					if leader != identity {
+						log.Printf("%s is collecting build logs", leader)
+					}
+				},
+			},
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"api-server/pkg/models"
+	"api-server/pkg/services"
+	"api-server/pkg/storage"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// maxLogLineSize는 한 줄로 취급할 수 있는 최대 로그 길이입니다
+	maxLogLineSize = 1024 * 1024
+
+	// logStreamInitialBackoff와 logStreamMaxBackoff는 로그 스트림 재시도 간격의 범위입니다
+	logStreamInitialBackoff = time.Second
+	logStreamMaxBackoff     = 30 * time.Second
+)
+
+// LogCollector는 빌드 Pod의 prepare/buildkit 컨테이너 로그를 LogService로 수집합니다
+//
+// 로그는 Kubernetes가 붙인 시각과 함께 저장되며, 재시작이나 리더 변경 후에도 로그를 다시 수집하지 않도록
+// Job과 컨테이너별로 저장소에 마지막으로 저장된 로그 시각 이후부터 이어서 수집합니다
+type LogCollector struct {
+	clientset  kubernetes.Interface
+	namespace  string
+	logService services.LogService
+
+	mu      sync.Mutex
+	streams map[string]struct{}
+}
+
+// NewLogCollector는 새로운 LogCollector를 생성합니다
+func NewLogCollector(clientset kubernetes.Interface, namespace string, logService services.LogService) *LogCollector {
+	return &LogCollector{
+		clientset:  clientset,
+		namespace:  namespace,
+		logService: logService,
+		streams:    make(map[string]struct{}),
+	}
+}
+
+// Run은 ctx가 종료될 때까지 빌드 Pod를 감시하며 컨테이너 로그를 수집합니다
+func (c *LogCollector) Run(ctx context.Context) {
+	factory := informers.NewSharedInformerFactoryWithOptions(c.clientset, 0,
+		informers.WithNamespace(c.namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = ManagedByLabel + "=" + ManagedByValue
+		}),
+	)
+
+	informer := factory.Core().V1().Pods().Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				c.handlePod(ctx, pod)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				c.handlePod(ctx, pod)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if pod, ok := obj.(*corev1.Pod); ok {
+				c.forgetPod(pod)
+			}
+		},
+	})
+
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	factory.Shutdown()
+}
+
+// handlePod는 시작된 컨테이너마다 로그 스트리밍을 시작합니다 (이미 스트리밍 중이면 무시)
+func (c *LogCollector) handlePod(ctx context.Context, pod *corev1.Pod) {
+	jobID := pod.Labels[JobIDLabel]
+	if jobID == "" {
+		return
+	}
+
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+
+	for _, status := range statuses {
+		// 컨테이너가 시작되기 전에는 로그를 요청할 수 없음
+		if status.State.Running == nil && status.State.Terminated == nil {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s", pod.UID, status.Name)
+		c.mu.Lock()
+		_, started := c.streams[key]
+		if !started {
+			c.streams[key] = struct{}{}
+		}
+		c.mu.Unlock()
+
+		if !started {
+			go c.stream(ctx, key, jobID, pod.Name, status.Name)
+		}
+	}
+}
+
+// forgetPod는 삭제된 Pod의 스트림을 정리합니다
+func (c *LogCollector) forgetPod(pod *corev1.Pod) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.streams {
+		if strings.HasPrefix(key, string(pod.UID)+"/") {
+			delete(c.streams, key)
+		}
+	}
+}
+
+// stream은 컨테이너 로그를 끝까지 수집합니다
+// 스트림이 실패하면 Pod가 실행 중인 동안 backoff 간격으로 마지막 수집 위치부터 다시 연결합니다
+func (c *LogCollector) stream(ctx context.Context, key, jobID, podName, container string) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.streams, key)
+		c.mu.Unlock()
+	}()
+
+	backoff := logStreamInitialBackoff
+	for {
+		err := c.follow(ctx, jobID, podName, container)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		if !c.podRunning(ctx, podName) {
+			log.Printf("failed to stream logs of %s/%s: %v", podName, container, err)
+			return
+		}
+
+		log.Printf("log stream of %s/%s interrupted, retrying in %s: %v", podName, container, backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, logStreamMaxBackoff)
+	}
+}
+
+// follow는 마지막 수집 위치 이후의 컨테이너 로그를 follow 하면서 한 줄씩 LogService에 추가합니다
+// 로그 스트림이 정상적으로 끝나면 nil을 반환합니다
+func (c *LogCollector) follow(ctx context.Context, jobID, podName, container string) error {
+	cursor := c.resumeTime(jobID, container)
+
+	options := &corev1.PodLogOptions{
+		Container:  container,
+		Follow:     true,
+		Timestamps: true,
+	}
+	if !cursor.IsZero() {
+		options.SinceTime = &metav1.Time{Time: cursor}
+	}
+
+	rc, err := c.clientset.CoreV1().Pods(c.namespace).GetLogs(podName, options).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLogLineSize)
+	for scanner.Scan() {
+		timestamp, line := splitLogTimestamp(scanner.Text())
+		if timestamp.IsZero() {
+			c.logService.AddLog(jobID, container, line)
+		} else {
+			// SinceTime은 초 단위이므로 이미 수집한 로그가 다시 올 수 있음
+			if !timestamp.After(cursor) {
+				continue
+			}
+			cursor = timestamp
+			c.logService.AddLogAt(jobID, container, line, timestamp)
+		}
+
+		// BuildKit이 레지스트리로 push를 시작하면 상태를 갱신
+		if container == "buildkit" && strings.Contains(line, "pushing layers") {
+			c.logService.SetJobStatus(jobID, models.JobStatusPushing)
+		}
+	}
+	return scanner.Err()
+}
+
+// resumeTime은 Job 컨테이너 로그를 이어서 수집할 시각을 반환합니다
+// 저장소에 마지막으로 저장된 해당 컨테이너 로그의 Kubernetes 시각이며, 없으면 처음부터 수집합니다
+func (c *LogCollector) resumeTime(jobID, container string) time.Time {
+	logs, _ := c.logService.QueryJobLogs(jobID, storage.LogQuery{Containers: []string{container}, Tail: 1})
+	if len(logs) == 0 {
+		return time.Time{}
+	}
+	cursor, _ := time.Parse(time.RFC3339Nano, logs[0].Timestamp)
+	return cursor
+}
+
+// podRunning은 로그 스트림을 다시 연결할 수 있도록 Pod가 아직 실행 중인지 확인합니다
+// Pod를 조회할 수 없는 일시적인 오류는 실행 중인 것으로 간주합니다
+func (c *LogCollector) podRunning(ctx context.Context, podName string) bool {
+	pod, err := c.clientset.CoreV1().Pods(c.namespace).Get(ctx, podName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return pod.Status.Phase == corev1.PodPending || pod.Status.Phase == corev1.PodRunning
+}
+
+// splitLogTimestamp는 Timestamps 옵션으로 받은 로그 줄을 시각과 메시지로 나눕니다
+// 시각이 없는 줄은 그대로 반환합니다
+func splitLogTimestamp(line string) (time.Time, string) {
+	prefix, message, found := strings.Cut(line, " ")
+	if !found {
+		return time.Time{}, line
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, prefix)
+	if err != nil {
+		return time.Time{}, line
+	}
+	return timestamp, message
+}
@@ -0,0 +1,323 @@
+package k8s
+
+import (
+	"bytes"
+
+	"api-server/pkg/models"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DockerfileKey는 ConfigMap에서 Dockerfile 내용을 담는 키입니다
+	DockerfileKey = "Dockerfile"
+	// ContextTokenKey는 Secret에서 빌드 컨텍스트 내려받기 토큰을 담는 키입니다
+	ContextTokenKey = "context-token"
+
+	// JobTTLSecondsAfterFinished는 종료된 빌드 Job을 Kubernetes가 삭제하기까지의 시간입니다
+	// 로그 보관 기간은 이보다 짧을 수 없습니다
+	JobTTLSecondsAfterFinished = 300
+
+	// dockerfileDir은 prepare 컨테이너에 Dockerfile ConfigMap을 마운트하는 경로입니다
+	dockerfileDir = "/dockerfile"
+	// contextTokenDir은 prepare 컨테이너에 컨텍스트 토큰 Secret을 마운트하는 경로입니다
+	contextTokenDir = "/context-token"
+	// workspaceDir은 prepare 컨테이너가 빌드 컨텍스트를 준비하는 경로입니다
+	workspaceDir = "/workspace"
+)
+
+// Manifest는 빌드 하나를 구성하는 Kubernetes 리소스 묶음입니다
+// ContextSecret은 업로드된 빌드 컨텍스트가 있을 때만 생성됩니다
+type Manifest struct {
+	ConfigMap     *corev1.ConfigMap
+	ContextSecret *corev1.Secret
+	Job           *batchv1.Job
+}
+
+// NewManifest는 빌드 요청으로부터 Dockerfile ConfigMap과 BuildKit(rootless) Job을 생성합니다
+// Dockerfile은 쉘을 거치지 않고 ConfigMap 볼륨으로 전달되므로 내용과 무관하게 매니페스트 구조가 유지됩니다
+func NewManifest(namespace, jobID string, req models.BuildJobRequest) (*Manifest, error) {
+	imageReference, err := ImageReference(req.JobName, req.ImageName)
+	if err != nil {
+		return nil, err
+	}
+
+	labels := map[string]string{
+		ManagedByLabel: ManagedByValue,
+		JobNameLabel:   req.JobName,
+		JobIDLabel:     jobID,
+	}
+
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DockerfileConfigMapName(req.JobName, jobID),
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Data: map[string]string{},
+	}
+	// 업로드된 컨텍스트에 Dockerfile이 포함된 경우 dockerfile_content를 생략할 수 있음
+	if req.DockerfileContent != "" {
+		configMap.Data[DockerfileKey] = req.DockerfileContent
+	}
+
+	// 컨텍스트 내려받기 토큰은 ConfigMap이 아닌 Job별 Secret으로 전달
+	var contextSecret *corev1.Secret
+	if req.Context != nil {
+		contextSecret = &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ContextSecretName(req.JobName, jobID),
+				Namespace: namespace,
+				Labels:    labels,
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{ContextTokenKey: []byte(req.Context.Token)},
+		}
+	}
+
+	// Git 저장소를 빌드하면 prepare 컨테이너가 저장소를 클론하고, 그 안의 경로로 빌드
+	prepare, source := prepareContainer(req.Context), workspaceSource
+	if req.Git != nil {
+		prepare, source = gitPrepareContainer(*req.Git), gitBuildSource(*req.Git, req.DockerfileContent != "")
+	}
+
+	job := &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      req.JobName,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			TTLSecondsAfterFinished: int32Ptr(JobTTLSecondsAfterFinished),
+			BackoffLimit:            int32Ptr(3),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:  corev1.RestartPolicyNever,
+					InitContainers: []corev1.Container{prepare},
+					Containers:     []corev1.Container{buildkitContainer(imageReference, req.PushRegistry, source, buildctlOpts(req, imageReference))},
+					Volumes: []corev1.Volume{
+						{
+							Name: "dockerfile",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+								},
+							},
+						},
+						{Name: "workspace", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+						{Name: "buildkitd", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+					},
+				},
+			},
+		},
+	}
+
+	// registry 캐시를 내보낼 때도 레지스트리 인증이 필요함
+	// 캐시만 사용하는 빌드는 인증 없이 접근 가능한 캐시 레지스트리일 수 있으므로 Secret이 없어도 Pod를 시작
+	if req.PushRegistry || (req.Cache != nil && req.Cache.Type == CacheRegistry) {
+		mountRegistrySecret(&job.Spec.Template.Spec, registrySecretName(req), !req.PushRegistry)
+	}
+	if contextSecret != nil {
+		mountContextToken(&job.Spec.Template.Spec, contextSecret.Name)
+	}
+	mountBuildSecrets(&job.Spec.Template.Spec, req)
+	mountCache(&job.Spec.Template.Spec, req.Cache)
+
+	return &Manifest{
+		ConfigMap:     configMap,
+		ContextSecret: contextSecret,
+		Job:           job,
+	}, nil
+}
+
+// DockerfileConfigMapName은 Job의 Dockerfile을 담는 ConfigMap 이름을 반환합니다
+// 같은 이름의 재빌드와 겹치지 않도록 Job ID를 포함합니다
+func DockerfileConfigMapName(jobName, jobID string) string {
+	return jobName + "-" + jobID + "-dockerfile"
+}
+
+// ContextSecretName은 Job의 빌드 컨텍스트 내려받기 토큰을 담는 Secret 이름을 반환합니다
+func ContextSecretName(jobName, jobID string) string {
+	return jobName + "-" + jobID + "-context"
+}
+
+// YAML은 매니페스트를 kubectl apply -f 로 적용 가능한 multi-document YAML로 직렬화합니다
+// 컨텍스트 토큰이 서버 디스크에 남지 않도록 ContextSecret은 포함하지 않습니다
+func (m *Manifest) YAML() ([]byte, error) {
+	var buf bytes.Buffer
+	for i, obj := range []interface{}{m.ConfigMap, m.Job} {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// prepareContainer는 ConfigMap의 Dockerfile을 workspace로 복사하는 init 컨테이너입니다
+// 업로드된 빌드 컨텍스트가 있으면 api-server에서 내려받아 digest를 확인한 뒤 workspace에 풀고,
+// dockerfile_content가 함께 지정되었으면 컨텍스트의 Dockerfile 대신 사용합니다
+func prepareContainer(buildContext *models.BuildContext) corev1.Container {
+	container := corev1.Container{
+		Name:    "prepare",
+		Image:   "busybox:latest",
+		Command: []string{"cp", dockerfileDir + "/" + DockerfileKey, "/workspace/" + DockerfileKey},
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:  int64Ptr(1000),
+			RunAsGroup: int64Ptr(1000),
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "dockerfile", ReadOnly: true, MountPath: dockerfileDir},
+			{Name: "workspace", MountPath: "/workspace"},
+		},
+	}
+
+	if buildContext != nil {
+		// 요청 값은 스크립트에 넣지 않고 환경 변수와 마운트된 파일로만 전달
+		container.Command = []string{"sh", "-c", prepareContextScript}
+		container.Env = []corev1.EnvVar{
+			{Name: "CONTEXT_URL", Value: buildContext.URL},
+			{Name: "CONTEXT_SHA256", Value: buildContext.Digest},
+		}
+	}
+	return container
+}
+
+// mountContextToken은 컨텍스트 토큰 Secret을 prepare 컨테이너에 마운트합니다
+func mountContextToken(spec *corev1.PodSpec, secretName string) {
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: "context-token",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: secretName},
+		},
+	})
+	prepare := &spec.InitContainers[0]
+	prepare.VolumeMounts = append(prepare.VolumeMounts, corev1.VolumeMount{
+		Name:      "context-token",
+		ReadOnly:  true,
+		MountPath: contextTokenDir,
+	})
+}
+
+// prepareContextScript는 업로드된 빌드 컨텍스트를 workspace에 준비하는 스크립트입니다
+const prepareContextScript = `set -e
+wget -q -O /tmp/context.tar.gz --header "Authorization: Bearer $(cat ` + contextTokenDir + `/` + ContextTokenKey + `)" "$CONTEXT_URL"
+echo "$CONTEXT_SHA256  /tmp/context.tar.gz" | sha256sum -c -
+tar -xzf /tmp/context.tar.gz -C /workspace
+rm /tmp/context.tar.gz
+if [ -f ` + dockerfileDir + `/` + DockerfileKey + ` ]; then cp ` + dockerfileDir + `/` + DockerfileKey + ` /workspace/` + DockerfileKey + `; fi
+echo "Build context extracted"`
+
+// buildSource는 buildctl에 전달하는 빌드 컨텍스트와 Dockerfile 위치입니다
+type buildSource struct {
+	contextDir     string
+	dockerfileDir  string
+	dockerfileName string
+}
+
+// workspaceSource는 prepare 컨테이너가 workspace 최상위에 준비한 컨텍스트와 Dockerfile입니다
+var workspaceSource = buildSource{
+	contextDir:     workspaceDir,
+	dockerfileDir:  workspaceDir,
+	dockerfileName: DockerfileKey,
+}
+
+// buildctlOpts는 빌드 옵션, Secret, 캐시 설정을 buildctl 인자로 변환합니다
+func buildctlOpts(req models.BuildJobRequest, imageReference string) []string {
+	opts := frontendOpts(req)
+	opts = append(opts, secretArgs(req)...)
+	return append(opts, cacheArgs(req.Cache, imageReference)...)
+}
+
+// buildkitContainer는 buildctl-daemonless.sh로 이미지를 빌드하는 컨테이너입니다
+// opts는 buildctlOpts로 만든 빌드 옵션, Secret, 캐시 인자입니다
+func buildkitContainer(imageReference string, push bool, source buildSource, opts []string) corev1.Container {
+	args := []string{
+		"build",
+		"--frontend", "dockerfile.v0",
+		"--local", "context=" + source.contextDir,
+		"--local", "dockerfile=" + source.dockerfileDir,
+	}
+	if source.dockerfileName != DockerfileKey {
+		args = append(args, "--opt", "filename="+source.dockerfileName)
+	}
+	args = append(args, opts...)
+	args = append(args, "--output", OutputArg(imageReference, push))
+
+	return corev1.Container{
+		Name:            "buildkit",
+		Image:           "moby/buildkit:master-rootless",
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Env: []corev1.EnvVar{
+			{Name: "BUILDKITD_FLAGS", Value: "--oci-worker-no-process-sandbox"},
+		},
+		Command: []string{"buildctl-daemonless.sh"},
+		Args:    args,
+		SecurityContext: &corev1.SecurityContext{
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeUnconfined,
+			},
+			RunAsUser:  int64Ptr(1000),
+			RunAsGroup: int64Ptr(1000),
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "workspace", ReadOnly: true, MountPath: "/workspace"},
+			{Name: "buildkitd", MountPath: "/home/user/.local/share/buildkit"},
+		},
+	}
+}
+
+// registrySecretName은 요청에 지정된 레지스트리 Secret 이름 또는 기본값을 반환합니다
+func registrySecretName(req models.BuildJobRequest) string {
+	if req.RegistrySecret != "" {
+		return req.RegistrySecret
+	}
+	return DefaultRegistrySecret
+}
+
+// mountRegistrySecret은 dockerconfigjson Secret을 buildkit 컨테이너의 DOCKER_CONFIG로 마운트합니다
+// optional이면 Secret이 없어도 Pod가 ContainerCreating에 머물지 않고 인증 없이 빌드합니다
+func mountRegistrySecret(podSpec *corev1.PodSpec, secretName string, optional bool) {
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "registry-credentials",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: secretName,
+				Items: []corev1.KeyToPath{
+					{Key: corev1.DockerConfigJsonKey, Path: "config.json"},
+				},
+				Optional: boolPtr(optional),
+			},
+		},
+	})
+
+	buildkit := &podSpec.Containers[0]
+	buildkit.Env = append(buildkit.Env, corev1.EnvVar{Name: "DOCKER_CONFIG", Value: dockerConfigDir})
+	buildkit.VolumeMounts = append(buildkit.VolumeMounts, corev1.VolumeMount{
+		Name:      "registry-credentials",
+		ReadOnly:  true,
+		MountPath: dockerConfigDir,
+	})
+}
@@ -0,0 +1,62 @@
+package k8s
+
+import (
+	"crypto/rand"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// MaxJobNameLength는 Job 이름의 최대 길이입니다
+	// Pod에 job-name 라벨로 복사되므로 라벨 값 제한(63자)을 따릅니다
+	MaxJobNameLength = validation.DNS1123LabelMaxLength
+
+	// generatedSuffixLength는 자동 생성 이름에 붙는 임의 접미사 길이입니다
+	generatedSuffixLength = 5
+)
+
+// invalidNameChars는 DNS-1123 label에 허용되지 않는 문자 패턴입니다
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// ValidateJobName은 Job 이름이 Kubernetes DNS-1123 label 규칙을 따르는지 검사하고
+// 위반한 규칙 목록을 반환합니다 (위반이 없으면 빈 슬라이스)
+func ValidateJobName(name string) []string {
+	var violations []string
+
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		violations = append(violations, "must not contain path separators or '..'")
+	}
+
+	return append(violations, validation.IsDNS1123Label(name)...)
+}
+
+// GenerateJobName은 요청된 이름을 DNS-1123 label로 정리한 뒤 임의 접미사를 붙여 고유한 이름을 생성합니다
+// 예: "My_App.v2" → "my-app-v2-x7k2p"
+func GenerateJobName(requested string) string {
+	base := invalidNameChars.ReplaceAllString(strings.ToLower(requested), "-")
+	base = strings.Trim(base, "-")
+
+	maxBaseLength := MaxJobNameLength - generatedSuffixLength - 1
+	if len(base) > maxBaseLength {
+		base = strings.TrimRight(base[:maxBaseLength], "-")
+	}
+	if base == "" {
+		base = "build"
+	}
+
+	return base + "-" + randomSuffix(generatedSuffixLength)
+}
+
+// randomSuffix는 소문자와 숫자로 이루어진 임의 문자열을 생성합니다
+func randomSuffix(length int) string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+	buf := make([]byte, length)
+	rand.Read(buf)
+	for i := range buf {
+		buf[i] = alphabet[int(buf[i])%len(alphabet)]
+	}
+	return string(buf)
+}
@@ -0,0 +1,161 @@
+package k8s
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"api-server/pkg/models"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// DefaultSSHID는 ssh 항목에 id가 없을 때 사용하는 id입니다 (RUN --mount=type=ssh의 기본값)
+	DefaultSSHID = "default"
+
+	// maxBuildSecrets는 secrets, ssh 각각에 지정할 수 있는 최대 항목 수입니다
+	maxBuildSecrets = 16
+
+	// buildSecretsDir은 buildkit 컨테이너에 빌드 Secret을 마운트하는 경로입니다
+	buildSecretsDir = "/run/build-secrets"
+	// buildSSHDir은 buildkit 컨테이너에 SSH 개인 키를 마운트하는 경로입니다
+	buildSSHDir = "/run/build-ssh"
+)
+
+// secretIDPattern은 secret/ssh id 패턴입니다
+// buildctl 인자(id=...,src=...)와 마운트 경로에 사용되므로 ',', '=', '/'를 허용하지 않습니다
+var secretIDPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,63}$`)
+
+// ValidateBuildSecrets는 secrets와 ssh 항목을 검사하고 위반한 규칙 목록을 반환합니다 (위반이 없으면 빈 슬라이스)
+func ValidateBuildSecrets(req models.BuildJobRequest) []string {
+	var violations []string
+
+	if len(req.Secrets) > maxBuildSecrets {
+		violations = append(violations, fmt.Sprintf("secrets must not have more than %d entries", maxBuildSecrets))
+	}
+	ids := map[string]bool{}
+	for _, secret := range req.Secrets {
+		violations = append(violations, validateSecretRef("secrets", secret.ID, secret.SecretName, secretKey(secret))...)
+		if ids[secret.ID] {
+			violations = append(violations, fmt.Sprintf("secrets id %q is listed more than once", secret.ID))
+		}
+		ids[secret.ID] = true
+	}
+
+	if len(req.SSH) > maxBuildSecrets {
+		violations = append(violations, fmt.Sprintf("ssh must not have more than %d entries", maxBuildSecrets))
+	}
+	ids = map[string]bool{}
+	for _, ssh := range req.SSH {
+		id := sshID(ssh)
+		violations = append(violations, validateSecretRef("ssh", id, ssh.SecretName, sshKey(ssh))...)
+		if ids[id] {
+			violations = append(violations, fmt.Sprintf("ssh id %q is listed more than once", id))
+		}
+		ids[id] = true
+	}
+
+	return violations
+}
+
+// ValidateBuildSecretAccess는 secrets와 ssh가 참조하는 Secret이 서버가 허용한 Secret인지 검사하고
+// 위반한 규칙 목록을 반환합니다
+// 빌드 네임스페이스에는 api-server의 저장소 접속 정보 같은 Secret도 있으므로 허용 목록에 없는 Secret은 마운트하지 않습니다
+func ValidateBuildSecretAccess(req models.BuildJobRequest, allowed map[string]bool) []string {
+	var violations []string
+	for _, secret := range req.Secrets {
+		if !allowed[secret.SecretName] {
+			violations = append(violations, fmt.Sprintf("secrets secret_name %q is not allowed for builds", secret.SecretName))
+		}
+	}
+	for _, ssh := range req.SSH {
+		if !allowed[ssh.SecretName] {
+			violations = append(violations, fmt.Sprintf("ssh secret_name %q is not allowed for builds", ssh.SecretName))
+		}
+	}
+	return violations
+}
+
+// validateSecretRef는 id, Secret 이름, 키를 검사합니다
+func validateSecretRef(field, id, secretName, key string) []string {
+	var violations []string
+	if !secretIDPattern.MatchString(id) {
+		violations = append(violations, fmt.Sprintf("%s id %q must consist of alphanumerics, '.', '_' or '-'", field, id))
+	}
+	if len(validation.IsDNS1123Subdomain(secretName)) > 0 {
+		violations = append(violations, fmt.Sprintf("%s secret_name %q must be a valid Secret name", field, secretName))
+	}
+	if len(validation.IsConfigMapKey(key)) > 0 {
+		violations = append(violations, fmt.Sprintf("%s key %q must be a valid Secret key", field, key))
+	}
+	return violations
+}
+
+// secretKey는 Secret에서 사용할 키를 반환합니다 (기본값: id)
+func secretKey(secret models.BuildSecret) string {
+	if secret.Key != "" {
+		return secret.Key
+	}
+	return secret.ID
+}
+
+// sshID는 ssh 항목의 id를 반환합니다 (기본값: default)
+func sshID(ssh models.BuildSSH) string {
+	if ssh.ID != "" {
+		return ssh.ID
+	}
+	return DefaultSSHID
+}
+
+// sshKey는 개인 키를 담은 Secret 키를 반환합니다 (기본값: ssh-privatekey)
+func sshKey(ssh models.BuildSSH) string {
+	if ssh.Key != "" {
+		return ssh.Key
+	}
+	return corev1.SSHAuthPrivateKey
+}
+
+// secretArgs는 마운트된 Secret 파일을 가리키는 buildctl --secret, --ssh 인자를 생성합니다
+// 인자에는 파일 경로만 들어가므로 Secret 값은 매니페스트에 기록되지 않습니다
+func secretArgs(req models.BuildJobRequest) []string {
+	var args []string
+	for _, secret := range req.Secrets {
+		args = append(args, "--secret", "id="+secret.ID+",src="+buildSecretsDir+"/"+secret.ID+"/value")
+	}
+	for _, ssh := range req.SSH {
+		id := sshID(ssh)
+		args = append(args, "--ssh", id+"="+buildSSHDir+"/"+id+"/key")
+	}
+	return args
+}
+
+// mountBuildSecrets는 요청된 Secret을 buildkit 컨테이너에 읽기 전용으로 마운트합니다
+// 필요한 키만 마운트하며, Secret 값은 kubelet이 Pod에 직접 전달합니다
+func mountBuildSecrets(podSpec *corev1.PodSpec, req models.BuildJobRequest) {
+	buildkit := &podSpec.Containers[0]
+	mount := func(volumeName, secretName, key, path, mountPath string) {
+		podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secretName,
+					Items:      []corev1.KeyToPath{{Key: key, Path: path}},
+				},
+			},
+		})
+		buildkit.VolumeMounts = append(buildkit.VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName,
+			ReadOnly:  true,
+			MountPath: mountPath,
+		})
+	}
+
+	for i, secret := range req.Secrets {
+		mount("build-secret-"+strconv.Itoa(i), secret.SecretName, secretKey(secret), "value", buildSecretsDir+"/"+secret.ID)
+	}
+	for i, ssh := range req.SSH {
+		mount("build-ssh-"+strconv.Itoa(i), ssh.SecretName, sshKey(ssh), "key", buildSSHDir+"/"+sshID(ssh))
+	}
+}
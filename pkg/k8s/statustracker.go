@@ -0,0 +1,167 @@
+package k8s
+
+import (
+	"context"
+
+	"api-server/pkg/models"
+	"api-server/pkg/services"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// StatusTracker는 Job/Pod 변경 이벤트로부터 빌드 Job 상태를 갱신합니다
+type StatusTracker struct {
+	clientset  kubernetes.Interface
+	namespace  string
+	logService services.LogService
+
+	// jobService가 있으면 Git 저장소에서 체크아웃된 커밋을 Job 메타데이터에 기록합니다
+	jobService services.JobService
+}
+
+// StatusTrackerOption은 StatusTracker의 선택적 설정입니다
+type StatusTrackerOption func(*StatusTracker)
+
+// WithGitCommits는 prepare 컨테이너가 체크아웃한 커밋을 jobService의 Job 메타데이터에 기록하도록 설정합니다
+func WithGitCommits(jobService services.JobService) StatusTrackerOption {
+	return func(t *StatusTracker) {
+		t.jobService = jobService
+	}
+}
+
+// NewStatusTracker는 새로운 StatusTracker를 생성합니다
+func NewStatusTracker(clientset kubernetes.Interface, namespace string, logService services.LogService, opts ...StatusTrackerOption) *StatusTracker {
+	t := &StatusTracker{
+		clientset:  clientset,
+		namespace:  namespace,
+		logService: logService,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Run은 ctx가 종료될 때까지 빌드 Job과 Pod를 감시합니다
+func (t *StatusTracker) Run(ctx context.Context) {
+	factory := informers.NewSharedInformerFactoryWithOptions(t.clientset, 0,
+		informers.WithNamespace(t.namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = ManagedByLabel + "=" + ManagedByValue
+		}),
+	)
+
+	factory.Batch().V1().Jobs().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if job, ok := obj.(*batchv1.Job); ok {
+				t.handleJob(job)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if job, ok := obj.(*batchv1.Job); ok {
+				t.handleJob(job)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if job, ok := obj.(*batchv1.Job); ok {
+				// 완료 전에 Job이 삭제되면 취소된 것으로 간주 (종료 상태는 유지됨)
+				t.logService.SetJobStatus(job.Labels[JobIDLabel], models.JobStatusCancelled)
+			}
+		},
+	})
+
+	factory.Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				t.handlePod(pod)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				t.handlePod(pod)
+			}
+		},
+	})
+
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	factory.Shutdown()
+}
+
+// handleJob은 Job condition으로부터 상태를 결정합니다
+func (t *StatusTracker) handleJob(job *batchv1.Job) {
+	jobID := job.Labels[JobIDLabel]
+	if jobID == "" {
+		return
+	}
+
+	status, _ := jobStatus(job)
+	t.logService.SetJobStatus(jobID, status)
+}
+
+// handlePod는 Pod 스케줄링과 컨테이너 실행 여부로부터 상태를 결정합니다
+func (t *StatusTracker) handlePod(pod *corev1.Pod) {
+	jobID := pod.Labels[JobIDLabel]
+	if jobID == "" {
+		return
+	}
+
+	if t.jobService != nil {
+		if commit, ok := GitCommit(pod); ok && t.jobService.RecordGitCommit(jobID, commit) {
+			t.logService.AddLog(jobID, "system", "Resolved git commit "+commit)
+		}
+	}
+
+	if status, ok := podStatus(pod); ok {
+		t.logService.SetJobStatus(jobID, status)
+	}
+}
+
+// jobStatus는 Job의 종료 condition을 상태로 변환하고 종료 여부를 함께 반환합니다
+func jobStatus(job *batchv1.Job) (models.JobStatus, bool) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return models.JobStatusSucceeded, true
+		case batchv1.JobFailed:
+			// activeDeadlineSeconds 초과는 만료로 구분
+			if condition.Reason == batchv1.JobReasonDeadlineExceeded {
+				return models.JobStatusExpired, true
+			}
+			return models.JobStatusFailed, true
+		}
+	}
+
+	return models.JobStatusPending, false
+}
+
+// podStatus는 Pod 상태를 빌드 진행 상태로 변환합니다
+func podStatus(pod *corev1.Pod) (models.JobStatus, bool) {
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Running != nil || status.State.Terminated != nil {
+			return models.JobStatusBuilding, true
+		}
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue {
+			return models.JobStatusScheduled, true
+		}
+	}
+
+	return "", false
+}
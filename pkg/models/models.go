@@ -1,11 +1,94 @@
 package models
 
+import "slices"
+
 // BuildJobRequest는 POST /api/buildjob 요청 구조입니다
 type BuildJobRequest struct {
 	JobName           string `json:"job_name"`
 	DockerfileContent string `json:"dockerfile_content"`
 	ImageName         string `json:"image_name,omitempty"`
 	PushRegistry      bool   `json:"push_registry,omitempty"`
+	RegistrySecret    string `json:"registry_secret,omitempty"`
+
+	// BuildKit dockerfile 프런트엔드 옵션 (--opt)
+	// build_args는 매니페스트에 그대로 기록되므로 비밀 값을 담으면 안 됩니다
+	BuildArgs map[string]string `json:"build_args,omitempty"`
+	Target    string            `json:"target,omitempty"`
+	Platforms []string          `json:"platforms,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	NoCache   bool              `json:"no_cache,omitempty"`
+
+	// Secrets와 SSH는 빌드 중에만 마운트할 Kubernetes Secret입니다 (값은 매니페스트와 로그에 남지 않음)
+	Secrets []BuildSecret `json:"secrets,omitempty"`
+	SSH     []BuildSSH    `json:"ssh,omitempty"`
+
+	// Cache는 레이어 캐시 설정입니다 (생략하면 서버 기본 정책을 사용)
+	Cache *BuildCache `json:"cache,omitempty"`
+
+	// Git은 빌드할 Git 저장소입니다 (지정하면 dockerfile_content는 선택)
+	Git *GitSource `json:"git,omitempty"`
+
+	// Context는 함께 업로드된 빌드 컨텍스트입니다 (multipart 요청에서 서버가 채움)
+	Context *BuildContext `json:"-"`
+}
+
+// BuildContext는 빌드 Pod가 내려받을 업로드된 빌드 컨텍스트(tar.gz) 정보입니다
+type BuildContext struct {
+	// URL은 prepare 컨테이너가 컨텍스트를 내려받는 주소입니다
+	URL string
+	// Token은 내려받을 때 Authorization 헤더로 전달하는 Job별 토큰입니다
+	Token string
+	// Digest는 tar.gz 파일의 sha256 (hex)입니다
+	Digest string
+	// Size는 tar.gz 파일의 크기입니다
+	Size int64
+}
+
+// BuildSecret은 RUN --mount=type=secret,id=<ID>로 사용할 Kubernetes Secret의 키입니다
+type BuildSecret struct {
+	// ID는 Dockerfile에서 참조하는 secret id입니다
+	ID string `json:"id"`
+	// SecretName은 빌드 네임스페이스의 Secret 이름입니다
+	SecretName string `json:"secret_name"`
+	// Key는 Secret에서 사용할 키입니다 (기본값: ID)
+	Key string `json:"key,omitempty"`
+}
+
+// BuildSSH는 RUN --mount=type=ssh,id=<ID>로 전달할 SSH 개인 키를 담은 Kubernetes Secret입니다
+type BuildSSH struct {
+	// ID는 Dockerfile에서 참조하는 ssh id입니다 (기본값: default)
+	ID string `json:"id,omitempty"`
+	// SecretName은 빌드 네임스페이스의 Secret 이름입니다
+	SecretName string `json:"secret_name"`
+	// Key는 개인 키를 담은 키입니다 (기본값: ssh-privatekey, kubernetes.io/ssh-auth 타입)
+	Key string `json:"key,omitempty"`
+}
+
+// BuildCache는 빌드 레이어 캐시를 가져오고 내보낼 위치입니다
+type BuildCache struct {
+	// Type은 캐시 종류입니다 (registry, local, inline, none)
+	Type string `json:"type"`
+	// Ref는 registry 캐시 이미지 참조입니다 (기본값: 서버 캐시 레지스트리의 <scope>:buildcache)
+	Ref string `json:"ref,omitempty"`
+	// Mode는 내보낼 캐시 범위입니다 (min: 최종 이미지 레이어, max: 모든 중간 레이어)
+	Mode string `json:"mode,omitempty"`
+	// Scope는 캐시를 공유하는 단위입니다 (기본값: job_name, 같은 프로젝트의 Job끼리 공유하려면 프로젝트 이름)
+	Scope string `json:"scope,omitempty"`
+
+	// ClaimName은 local 캐시를 담는 PVC 이름입니다 (서버 설정에서 채움)
+	ClaimName string `json:"-"`
+}
+
+// GitSource는 빌드 소스로 사용할 Git 저장소입니다
+type GitSource struct {
+	// URL은 저장소 주소입니다 (https://, http:// 또는 git://)
+	URL string `json:"url"`
+	// Ref는 체크아웃할 브랜치, 태그 또는 커밋입니다 (기본값: 원격 HEAD)
+	Ref string `json:"ref,omitempty"`
+	// ContextDir은 빌드 컨텍스트로 사용할 저장소 내 디렉터리입니다 (기본값: 저장소 루트)
+	ContextDir string `json:"context_dir,omitempty"`
+	// Dockerfile은 ContextDir 기준 Dockerfile 경로입니다 (기본값: Dockerfile)
+	Dockerfile string `json:"dockerfile,omitempty"`
 }
 
 // BuildJobResponse는 POST /api/buildjob 응답 구조입니다
@@ -14,29 +97,150 @@ type BuildJobResponse struct {
 	Message   string `json:"message"`
 	JobName   string `json:"job_name"`
 	JobID     string `json:"job_id"`
+	ImageName string `json:"image_name"`
 	Namespace string `json:"namespace"`
 	CreatedAt string `json:"created_at"`
 }
 
 // LogEntry는 로그 항목 구조입니다
 type LogEntry struct {
+	Seq       int64  `json:"seq"`
 	Timestamp string `json:"timestamp"`
 	Container string `json:"container"`
 	Message   string `json:"message"`
 	Level     string `json:"level"`
 }
 
-// LogsResponse는 GET /api/buildjob/{job_name}/logs 응답 구조입니다
+// JobDetail은 GET /api/buildjob/{job_id} 응답 구조입니다
+type JobDetail struct {
+	JobID           string    `json:"job_id"`
+	JobName         string    `json:"job_name"`
+	ImageName       string    `json:"image_name"`
+	Namespace       string    `json:"namespace"`
+	Status          JobStatus `json:"status"`
+	CreatedAt       string    `json:"created_at"`
+	UpdatedAt       string    `json:"updated_at"`
+	DurationSeconds int64     `json:"duration_seconds"`
+	GitURL          string    `json:"git_url,omitempty"`
+	GitRef          string    `json:"git_ref,omitempty"`
+	GitCommit       string    `json:"git_commit,omitempty"`
+}
+
+// JobListResponse는 GET /api/buildjob 응답 구조입니다
+type JobListResponse struct {
+	Jobs       []JobDetail `json:"jobs"`
+	Count      int         `json:"count"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// LogsResponse는 GET /api/buildjob/{job_id}/logs 응답 구조입니다
 type LogsResponse struct {
+	JobID      string     `json:"job_id"`
 	JobName    string     `json:"job_name"`
-	Status     string     `json:"status"`
+	Status     JobStatus  `json:"status"`
 	Logs       []LogEntry `json:"logs"`
 	TotalLines int        `json:"total_lines"`
+	NextSeq    int64      `json:"next_seq"`
+	HasMore    bool       `json:"has_more"`
+}
+
+// LogStreamMessage는 WebSocket 로그 스트림으로 전송되는 메시지입니다
+// Type이 log이면 Log에, end이면 Status에 내용이 담깁니다
+type LogStreamMessage struct {
+	Type   string             `json:"type"`
+	Log    *LogEntry          `json:"log,omitempty"`
+	Status *JobStatusResponse `json:"status,omitempty"`
+}
+
+// JobStatus는 빌드 Job의 상태입니다
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusScheduled JobStatus = "scheduled"
+	JobStatusBuilding  JobStatus = "building"
+	JobStatusPushing   JobStatus = "pushing"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
+	JobStatusExpired   JobStatus = "expired"
+)
+
+// IsValid는 정의된 상태 값인지 확인합니다
+func (s JobStatus) IsValid() bool {
+	return s.IsTerminal() || s.progress() > 0
+}
+
+// TerminalStatuses는 더 이상 바뀌지 않는 종료 상태 목록입니다
+var TerminalStatuses = []JobStatus{JobStatusSucceeded, JobStatusFailed, JobStatusCancelled, JobStatusExpired}
+
+// IsTerminal은 더 이상 바뀌지 않는 종료 상태인지 확인합니다
+func (s JobStatus) IsTerminal() bool {
+	return slices.Contains(TerminalStatuses, s)
+}
+
+// CanTransitionTo는 현재 상태에서 next 상태로 전이할 수 있는지 확인합니다
+// 종료 상태에서는 전이할 수 없고, 진행 상태는 앞으로만 진행합니다
+func (s JobStatus) CanTransitionTo(next JobStatus) bool {
+	if s.IsTerminal() || s == next {
+		return false
+	}
+	if next.IsTerminal() {
+		return true
+	}
+	return next.progress() > s.progress()
+}
+
+// progress는 진행 상태의 순서를 반환합니다
+func (s JobStatus) progress() int {
+	switch s {
+	case JobStatusPending:
+		return 1
+	case JobStatusScheduled:
+		return 2
+	case JobStatusBuilding:
+		return 3
+	case JobStatusPushing:
+		return 4
+	}
+	return 0
+}
+
+// JobState는 Job의 현재 상태와 마지막 변경 시각입니다
+type JobState struct {
+	Status    JobStatus `json:"status"`
+	UpdatedAt string    `json:"updated_at"`
+}
+
+// JobStatusResponse는 GET /api/buildjob/{job_id}/status 응답 구조입니다
+type JobStatusResponse struct {
+	JobID     string    `json:"job_id"`
+	JobName   string    `json:"job_name"`
+	Status    JobStatus `json:"status"`
+	UpdatedAt string    `json:"updated_at"`
 }
 
 // ErrorResponse는 에러 응답 구조입니다
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error   string   `json:"error"`
+	Details []string `json:"details,omitempty"`
+	JobID   string   `json:"job_id,omitempty"`
+}
+
+// Job은 등록된 빌드 Job의 메타데이터입니다
+type Job struct {
+	ID             string `json:"job_id"`
+	Name           string `json:"job_name"`
+	ImageName      string `json:"image_name"`
+	Namespace      string `json:"namespace"`
+	CreatedAt      string `json:"created_at"`
+	IdempotencyKey string `json:"-"`
+	RequestHash    string `json:"-"`
+
+	// Git 저장소를 빌드한 경우 요청된 저장소/ref와 실제로 체크아웃된 커밋
+	GitURL    string `json:"git_url,omitempty"`
+	GitRef    string `json:"git_ref,omitempty"`
+	GitCommit string `json:"git_commit,omitempty"`
 }
 
 // BuildRequest는 POST /api/build/create 요청 구조입니다 (레거시)
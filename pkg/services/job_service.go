@@ -0,0 +1,162 @@
+package services
+
+import (
+	"api-server/pkg/models"
+	"api-server/pkg/storage"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	// ErrJobNameConflict는 같은 이름의 Job이 아직 실행 중일 때 반환됩니다
+	ErrJobNameConflict = errors.New("job with the same name is still running")
+
+	// ErrIdempotentReplay는 같은 Idempotency-Key로 이미 등록된 Job이 있을 때 반환됩니다
+	ErrIdempotentReplay = errors.New("job already created with this idempotency key")
+
+	// ErrIdempotencyKeyReused는 Idempotency-Key가 다른 요청 내용으로 재사용되었을 때 반환됩니다
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different request")
+)
+
+// JobService는 빌드 Job 등록과 조회를 담당합니다
+type JobService interface {
+	// Register는 새로운 ID를 발급하여 Job을 등록하고 pending 상태로 로그를 초기화합니다
+	// 충돌하거나 재시도된 요청이면 기존 Job과 함께 에러를 반환합니다
+	Register(job models.Job) (models.Job, error)
+
+	// GetJob은 ID로 Job을 조회합니다
+	GetJob(id string) (models.Job, bool)
+
+	// FindByName은 같은 이름으로 가장 최근에 등록된 Job을 조회합니다
+	FindByName(name string) (models.Job, bool)
+
+	// List는 조건에 맞는 Job 목록과 다음 페이지 커서를 조회합니다
+	List(query storage.JobQuery) ([]models.Job, string, error)
+
+	// Delete는 Job 메타데이터를 삭제합니다
+	Delete(id string)
+
+	// RecordGitCommit은 Git 저장소에서 실제로 체크아웃된 커밋을 기록합니다
+	// 기록된 커밋이 바뀌었으면 true를 반환합니다
+	RecordGitCommit(id, commit string) bool
+}
+
+// DefaultJobService는 JobStore 기반 Job 서비스 구현입니다
+type DefaultJobService struct {
+	store      storage.JobStore
+	logService LogService
+
+	// mu는 중복 검사와 등록을 원자적으로 처리합니다
+	mu sync.Mutex
+}
+
+// NewJobService는 주어진 저장소를 사용하는 Job 서비스를 생성합니다
+// Job 상태는 logService에 기록된 상태를 사용합니다
+func NewJobService(store storage.JobStore, logService LogService) JobService {
+	return &DefaultJobService{
+		store:      store,
+		logService: logService,
+	}
+}
+
+// NewInMemoryJobService는 메모리 기반 Job 서비스를 생성합니다
+func NewInMemoryJobService(logService LogService) JobService {
+	return NewJobService(storage.NewMemoryJobStore(logService.GetJobStatus), logService)
+}
+
+// NewJobID는 시간 순으로 정렬 가능한 고유 Job ID(소문자 ULID)를 생성합니다
+// Kubernetes 라벨 값으로도 사용되므로 소문자로 변환합니다
+func NewJobID() string {
+	return strings.ToLower(ulid.Make().String())
+}
+
+// Register는 새로운 ID를 발급하여 Job을 등록하고 pending 상태로 로그를 초기화합니다
+// 같은 이름의 이전 Job이 종료되었다면 재빌드로 보고 별도의 ID로 등록합니다
+// 상태를 잠금 안에서 함께 만들어야 동시에 제출된 같은 이름의 Job이 실행 중인 Job으로 보입니다
+func (s *DefaultJobService) Register(job models.Job) (models.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 여러 replica가 공유하는 저장소는 중복 검사와 등록을 저장소에서 원자적으로 처리
+	if registrar, ok := s.store.(storage.JobRegistrar); ok {
+		job.ID = NewJobID()
+		existing, err := registrar.RegisterJob(job)
+		switch {
+		case errors.Is(err, storage.ErrDuplicateIdempotencyKey):
+			return existing, idempotencyError(existing, job)
+		case errors.Is(err, storage.ErrActiveJobName):
+			return existing, ErrJobNameConflict
+		case err != nil:
+			return models.Job{}, err
+		}
+		s.logService.AddLog(job.ID, "system", jobCreatedMessage)
+		return job, nil
+	}
+
+	if job.IdempotencyKey != "" {
+		if existing, exists := s.store.FindByIdempotencyKey(job.IdempotencyKey); exists {
+			return existing, idempotencyError(existing, job)
+		}
+	}
+
+	if existing, exists := s.store.FindByName(job.Name); exists && s.isActive(existing.ID) {
+		return existing, ErrJobNameConflict
+	}
+
+	job.ID = NewJobID()
+	s.store.SaveJob(job)
+	s.logService.CreateJobLogs(job.ID)
+	return job, nil
+}
+
+// idempotencyError는 같은 Idempotency-Key로 등록된 Job이 같은 요청으로 만들어졌는지에 따라 에러를 반환합니다
+func idempotencyError(existing, job models.Job) error {
+	if existing.RequestHash != job.RequestHash {
+		return ErrIdempotencyKeyReused
+	}
+	return ErrIdempotentReplay
+}
+
+// isActive는 Job이 아직 종료되지 않았는지 확인합니다
+func (s *DefaultJobService) isActive(id string) bool {
+	state, exists := s.logService.GetJobStatus(id)
+	return exists && !state.Status.IsTerminal()
+}
+
+// GetJob은 ID로 Job을 조회합니다
+func (s *DefaultJobService) GetJob(id string) (models.Job, bool) {
+	return s.store.GetJob(id)
+}
+
+// FindByName은 같은 이름으로 가장 최근에 등록된 Job을 조회합니다
+func (s *DefaultJobService) FindByName(name string) (models.Job, bool) {
+	return s.store.FindByName(name)
+}
+
+// List는 조건에 맞는 Job 목록과 다음 페이지 커서를 조회합니다
+func (s *DefaultJobService) List(query storage.JobQuery) ([]models.Job, string, error) {
+	return s.store.ListJobs(query)
+}
+
+// Delete는 Job 메타데이터를 삭제합니다
+func (s *DefaultJobService) Delete(id string) {
+	s.store.DeleteJob(id)
+}
+
+// RecordGitCommit은 Git 저장소에서 실제로 체크아웃된 커밋을 기록합니다
+// 기록된 커밋이 바뀌었으면 true를 반환합니다
+func (s *DefaultJobService) RecordGitCommit(id, commit string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.store.GetJob(id)
+	if !exists || job.GitURL == "" || job.GitCommit == commit {
+		return false
+	}
+	job.GitCommit = commit
+	s.store.SaveJob(job)
+	return true
+}
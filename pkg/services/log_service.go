@@ -4,52 +4,169 @@ import (
 	"api-server/pkg/models"
 	"api-server/pkg/storage"
 	"api-server/pkg/utils"
+	"fmt"
+	"log"
+	"sync"
+	"time"
 )
 
+// jobCreatedMessage는 Job이 등록될 때 남기는 첫 로그입니다
+const jobCreatedMessage = "Build job created successfully"
+
 // LogService는 로그 관련 비즈니스 로직을 담당합니다
 type LogService interface {
 	// CreateJobLogs는 새로운 Job의 로그를 초기화합니다
-	CreateJobLogs(jobName string)
+	CreateJobLogs(jobID string)
 
 	// AddLog는 로그 엔트리를 추가합니다
-	AddLog(jobName, container, message string)
+	AddLog(jobID, container, message string)
+
+	// AddLogAt은 Kubernetes에서 받은 시각으로 로그 엔트리를 추가합니다
+	AddLogAt(jobID, container, message string, timestamp time.Time)
 
 	// GetJobLogs는 특정 Job의 모든 로그를 조회합니다
-	GetJobLogs(jobName string) ([]models.LogEntry, bool)
+	GetJobLogs(jobID string) ([]models.LogEntry, bool)
+
+	// JobExists는 특정 Job의 로그가 존재하는지 로그를 읽지 않고 확인합니다
+	JobExists(jobID string) bool
+
+	// QueryJobLogs는 특정 Job의 로그 중 조건에 맞는 로그를 조회합니다
+	QueryJobLogs(jobID string, query storage.LogQuery) ([]models.LogEntry, bool)
+
+	// StreamJobLogs는 조건에 맞는 로그를 메모리에 모으지 않고 순서대로 fn에 전달합니다
+	StreamJobLogs(jobID string, query storage.LogQuery, fn func(models.LogEntry) error) (bool, error)
 
 	// DeleteJobLogs는 특정 Job의 로그를 삭제합니다
-	DeleteJobLogs(jobName string)
+	DeleteJobLogs(jobID string)
+
+	// SetJobStatus는 Job 상태를 전이시키고 변경 내역을 로그로 남깁니다
+	SetJobStatus(jobID string, status models.JobStatus)
+
+	// GetJobStatus는 특정 Job의 현재 상태를 조회합니다
+	GetJobStatus(jobID string) (models.JobState, bool)
+
+	// Subscribe는 특정 Job의 로그나 상태가 변경될 때 신호를 받는 채널과 구독 해제 함수를 반환합니다
+	Subscribe(jobID string) (<-chan struct{}, func())
+
+	// StoredJobIDs는 로그 저장소에 남아 있는 Job ID를 반환합니다
+	// 저장소가 나열을 지원하지 않으면 nil을 반환합니다
+	StoredJobIDs() ([]string, error)
 }
 
-// InMemoryLogService는 메모리 기반 로그 서비스 구현입니다
-type InMemoryLogService struct {
+// DefaultLogService는 LogStorage 기반 로그 서비스 구현입니다
+type DefaultLogService struct {
 	storage storage.LogStorage
+
+	// statusMu는 상태 조회와 전이를 원자적으로 처리합니다 (저장소가 StatusTransitioner가 아닐 때)
+	statusMu sync.Mutex
+}
+
+// NewLogService는 주어진 저장소를 사용하는 로그 서비스를 생성합니다
+func NewLogService(storage storage.LogStorage) LogService {
+	return &DefaultLogService{
+		storage: storage,
+	}
 }
 
 // NewInMemoryLogService는 새로운 메모리 기반 로그 서비스를 생성합니다
 func NewInMemoryLogService() LogService {
-	return &InMemoryLogService{
-		storage: storage.NewMemoryStorage(),
-	}
+	return NewLogService(storage.NewMemoryStorage())
 }
 
 // CreateJobLogs는 새로운 Job의 로그를 초기화합니다
-func (s *InMemoryLogService) CreateJobLogs(jobName string) {
-	s.storage.SaveLog(jobName, "system", "Build job created successfully", "info")
+func (s *DefaultLogService) CreateJobLogs(jobID string) {
+	s.storage.SaveLog(jobID, "system", jobCreatedMessage, "info")
+	s.storage.SaveStatus(jobID, models.JobStatusPending)
 }
 
 // AddLog는 로그 엔트리를 추가합니다
-func (s *InMemoryLogService) AddLog(jobName, container, message string) {
+func (s *DefaultLogService) AddLog(jobID, container, message string) {
 	level := utils.DetectLogLevel(message)
-	s.storage.SaveLog(jobName, container, message, level)
+	s.storage.SaveLog(jobID, container, message, level)
+}
+
+// AddLogAt은 Kubernetes에서 받은 시각으로 로그 엔트리를 추가합니다
+func (s *DefaultLogService) AddLogAt(jobID, container, message string, timestamp time.Time) {
+	level := utils.DetectLogLevel(message)
+	s.storage.SaveLogAt(jobID, container, message, level, timestamp)
 }
 
 // GetJobLogs는 특정 Job의 모든 로그를 조회합니다
-func (s *InMemoryLogService) GetJobLogs(jobName string) ([]models.LogEntry, bool) {
-	return s.storage.GetLogs(jobName)
+func (s *DefaultLogService) GetJobLogs(jobID string) ([]models.LogEntry, bool) {
+	return s.storage.GetLogs(jobID)
+}
+
+// JobExists는 특정 Job의 로그가 존재하는지 로그를 읽지 않고 확인합니다
+func (s *DefaultLogService) JobExists(jobID string) bool {
+	return s.storage.Exists(jobID)
+}
+
+// QueryJobLogs는 특정 Job의 로그 중 조건에 맞는 로그를 조회합니다
+func (s *DefaultLogService) QueryJobLogs(jobID string, query storage.LogQuery) ([]models.LogEntry, bool) {
+	return s.storage.QueryLogs(jobID, query)
+}
+
+// StreamJobLogs는 조건에 맞는 로그를 메모리에 모으지 않고 순서대로 fn에 전달합니다
+func (s *DefaultLogService) StreamJobLogs(jobID string, query storage.LogQuery, fn func(models.LogEntry) error) (bool, error) {
+	return s.storage.StreamLogs(jobID, query, fn)
 }
 
 // DeleteJobLogs는 특정 Job의 로그를 삭제합니다
-func (s *InMemoryLogService) DeleteJobLogs(jobName string) {
-	s.storage.DeleteLogs(jobName)
+func (s *DefaultLogService) DeleteJobLogs(jobID string) {
+	s.storage.DeleteLogs(jobID)
+}
+
+// StoredJobIDs는 로그 저장소에 남아 있는 Job ID를 반환합니다
+func (s *DefaultLogService) StoredJobIDs() ([]string, error) {
+	lister, ok := s.storage.(storage.JobLister)
+	if !ok {
+		return nil, nil
+	}
+	return lister.JobIDs()
+}
+
+// SetJobStatus는 Job 상태를 전이시키고 변경 내역을 로그로 남깁니다
+// 알 수 없는 Job, 종료된 Job, 이전 단계로의 전이 요청은 무시합니다
+func (s *DefaultLogService) SetJobStatus(jobID string, status models.JobStatus) {
+	message := fmt.Sprintf("Build job status changed to %s", status)
+
+	// 여러 replica가 공유하는 저장소는 조건부 변경으로 전이하고, 다른 replica가 먼저 바꿨으면 다시 판단
+	if transitioner, ok := s.storage.(storage.StatusTransitioner); ok {
+		for {
+			current, exists := s.storage.GetStatus(jobID)
+			if !exists || !current.Status.CanTransitionTo(status) {
+				return
+			}
+			changed, err := transitioner.TransitionStatus(jobID, current.Status, status, "system", message, utils.DetectLogLevel(message))
+			if err != nil {
+				log.Print(err)
+				return
+			}
+			if changed {
+				return
+			}
+		}
+	}
+
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+
+	current, exists := s.storage.GetStatus(jobID)
+	if !exists || !current.Status.CanTransitionTo(status) {
+		return
+	}
+
+	// 종료 상태를 본 구독자가 변경 로그까지 읽을 수 있도록 로그를 먼저 남김
+	s.AddLog(jobID, "system", message)
+	s.storage.SaveStatus(jobID, status)
+}
+
+// GetJobStatus는 특정 Job의 현재 상태를 조회합니다
+func (s *DefaultLogService) GetJobStatus(jobID string) (models.JobState, bool) {
+	return s.storage.GetStatus(jobID)
+}
+
+// Subscribe는 특정 Job의 로그나 상태가 변경될 때 신호를 받는 채널과 구독 해제 함수를 반환합니다
+func (s *DefaultLogService) Subscribe(jobID string) (<-chan struct{}, func()) {
+	return s.storage.Subscribe(jobID)
 }
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"api-server/pkg/models"
+	"api-server/pkg/storage"
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	// reapInterval은 보관 기간이 지난 Job을 찾는 주기입니다
+	reapInterval = time.Minute
+	// reapPageSize는 한 번에 조회하는 종료된 Job 수입니다
+	reapPageSize = 200
+)
+
+// LogReaper는 종료된 지 보관 기간이 지난 Job의 로그와 메타데이터를 삭제합니다
+// Kubernetes의 ttlSecondsAfterFinished와 같이 종료 시각(종료 상태로 바뀐 시각)부터 기간을 계산합니다
+type LogReaper struct {
+	logService LogService
+	jobService JobService
+	retention  time.Duration
+
+	// contexts는 종료된 Job의 빌드 컨텍스트를 바로 정리할 저장소입니다 (없으면 nil)
+	contexts *storage.ContextStore
+}
+
+// LogReaperOption은 LogReaper의 선택적 설정입니다
+type LogReaperOption func(*LogReaper)
+
+// WithBuildContexts는 종료되었거나 로그가 삭제된 Job의 빌드 컨텍스트를 보관 기간과 관계없이 정리하도록 설정합니다
+func WithBuildContexts(store *storage.ContextStore) LogReaperOption {
+	return func(r *LogReaper) {
+		r.contexts = store
+	}
+}
+
+// NewLogReaper는 새로운 LogReaper를 생성합니다
+func NewLogReaper(logService LogService, jobService JobService, retention time.Duration, opts ...LogReaperOption) *LogReaper {
+	r := &LogReaper{
+		logService: logService,
+		jobService: jobService,
+		retention:  retention,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Run은 ctx가 종료될 때까지 주기적으로 Reap을 실행합니다
+func (r *LogReaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(reapInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if deleted := r.Reap(now); deleted > 0 {
+				log.Printf("Deleted logs of %d jobs finished more than %s ago", deleted, r.retention)
+			}
+		}
+	}
+}
+
+// Reap은 now 기준으로 보관 기간이 지난 Job을 삭제하고 삭제한 Job 수를 반환합니다
+// JobStore에 없는 Job(재시작 전에 저장된 file/redis 로그 등)도 로그 저장소에서 찾아 삭제합니다
+func (r *LogReaper) Reap(now time.Time) int {
+	r.pruneContexts()
+	return r.reapJobs(now) + r.reapStoredLogs(now)
+}
+
+// reapJobs는 JobStore에 등록된 종료된 Job 중 보관 기간이 지난 Job을 삭제합니다
+func (r *LogReaper) reapJobs(now time.Time) int {
+	deleted := 0
+	query := storage.JobQuery{
+		Statuses: models.TerminalStatuses,
+		SortBy:   storage.SortByCreatedAt,
+		Limit:    reapPageSize,
+	}
+
+	for {
+		jobs, next, err := r.jobService.List(query)
+		if err != nil {
+			log.Printf("failed to list finished jobs: %v", err)
+			return deleted
+		}
+
+		for _, job := range jobs {
+			if !r.expired(job.ID, now) {
+				continue
+			}
+
+			r.logService.DeleteJobLogs(job.ID)
+			r.jobService.Delete(job.ID)
+			deleted++
+		}
+
+		// 삭제해도 커서는 마지막 Job의 정렬 키를 기준으로 하므로 다음 페이지를 그대로 이어서 조회
+		if next == "" {
+			return deleted
+		}
+		query.Cursor = next
+	}
+}
+
+// reapStoredLogs는 로그 저장소에 남은 Job 중 보관 기간이 지난 Job을 삭제합니다
+func (r *LogReaper) reapStoredLogs(now time.Time) int {
+	ids, err := r.logService.StoredJobIDs()
+	if err != nil {
+		log.Printf("failed to list stored logs: %v", err)
+		return 0
+	}
+
+	deleted := 0
+	for _, id := range ids {
+		if !r.expired(id, now) {
+			continue
+		}
+		r.logService.DeleteJobLogs(id)
+		r.jobService.Delete(id)
+		deleted++
+	}
+	return deleted
+}
+
+// expired는 Job이 종료된 지 보관 기간이 지났는지 확인합니다
+func (r *LogReaper) expired(jobID string, now time.Time) bool {
+	state, exists := r.logService.GetJobStatus(jobID)
+	if !exists || !state.Status.IsTerminal() {
+		return false
+	}
+	finishedAt, err := time.Parse(time.RFC3339, state.UpdatedAt)
+	return err == nil && now.Sub(finishedAt) >= r.retention
+}
+
+// pruneContexts는 더 이상 내려받을 빌드 Pod가 없는 Job의 빌드 컨텍스트를 삭제합니다
+func (r *LogReaper) pruneContexts() {
+	if r.contexts == nil {
+		return
+	}
+
+	ids, err := r.contexts.JobIDs()
+	if err != nil {
+		log.Printf("failed to list build contexts: %v", err)
+		return
+	}
+	for _, id := range ids {
+		if state, exists := r.logService.GetJobStatus(id); !exists || state.Status.IsTerminal() {
+			r.contexts.Delete(id)
+		}
+	}
+}
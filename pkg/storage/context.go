@@ -0,0 +1,281 @@
+package storage
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultContextMaxSize는 업로드할 수 있는 빌드 컨텍스트(tar.gz)의 기본 최대 크기입니다
+	DefaultContextMaxSize = 100 * 1024 * 1024
+	// DefaultContextMaxExtractedSize는 압축을 푼 빌드 컨텍스트의 기본 최대 크기입니다
+	DefaultContextMaxExtractedSize = 1024 * 1024 * 1024
+	// contextMaxEntries는 빌드 컨텍스트에 포함될 수 있는 최대 파일 수입니다
+	contextMaxEntries = 100000
+)
+
+var (
+	// ErrContextTooLarge는 빌드 컨텍스트가 크기 제한을 넘을 때 반환됩니다
+	ErrContextTooLarge = errors.New("build context is too large")
+	// ErrInvalidContext는 빌드 컨텍스트가 올바른 tar.gz가 아니거나 허용되지 않는 항목을 포함할 때 반환됩니다
+	ErrInvalidContext = errors.New("invalid build context")
+)
+
+// ContextStore는 업로드된 빌드 컨텍스트를 빌드 Pod가 내려받을 때까지 디스크에 보관합니다
+//
+//	<dir>/<job_id>.tar.gz  빌드 컨텍스트
+//	<dir>/<job_id>.token   내려받기 토큰
+//
+// 업로드는 먼저 임시 파일로 받아 검증(Stage)한 뒤 Job이 등록되면 Job ID로 옮깁니다(Commit)
+type ContextStore struct {
+	dir              string
+	maxSize          int64
+	maxExtractedSize int64
+}
+
+// StagedContext는 검증을 마치고 Job 등록을 기다리는 빌드 컨텍스트입니다
+type StagedContext struct {
+	path string
+
+	// Digest는 tar.gz 파일의 sha256 (hex)입니다
+	Digest string
+	// Size는 tar.gz 파일의 크기입니다
+	Size int64
+	// HasDockerfile은 컨텍스트 최상위에 Dockerfile이 있는지 나타냅니다
+	HasDockerfile bool
+}
+
+// ContextStoreOption은 ContextStore의 선택적 설정입니다
+type ContextStoreOption func(*ContextStore)
+
+// WithContextMaxSize는 업로드할 수 있는 tar.gz의 최대 크기를 설정합니다
+func WithContextMaxSize(size int64) ContextStoreOption {
+	return func(s *ContextStore) {
+		s.maxSize = size
+	}
+}
+
+// WithContextMaxExtractedSize는 압축을 푼 컨텍스트의 최대 크기를 설정합니다
+func WithContextMaxExtractedSize(size int64) ContextStoreOption {
+	return func(s *ContextStore) {
+		s.maxExtractedSize = size
+	}
+}
+
+// NewContextStore는 dir 아래에 빌드 컨텍스트를 보관하는 저장소를 생성합니다
+func NewContextStore(dir string, opts ...ContextStoreOption) (*ContextStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create context directory %s: %w", dir, err)
+	}
+
+	s := &ContextStore{
+		dir:              dir,
+		maxSize:          DefaultContextMaxSize,
+		maxExtractedSize: DefaultContextMaxExtractedSize,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// MaxSize는 업로드할 수 있는 tar.gz의 최대 크기입니다
+func (s *ContextStore) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Stage는 r의 tar.gz를 임시 파일로 저장하고 크기와 항목을 검증합니다
+// 검증에 실패하면 임시 파일을 지우고 ErrContextTooLarge 또는 ErrInvalidContext를 반환합니다
+func (s *ContextStore) Stage(r io.Reader) (*StagedContext, error) {
+	file, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return nil, err
+	}
+	staged := &StagedContext{path: file.Name()}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), io.LimitReader(r, s.maxSize+1))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && size > s.maxSize {
+		err = fmt.Errorf("%w: exceeds %d bytes", ErrContextTooLarge, s.maxSize)
+	}
+	if err == nil {
+		staged.HasDockerfile, err = s.validate(staged.path)
+	}
+	if err != nil {
+		s.Discard(staged)
+		return nil, err
+	}
+
+	staged.Digest = hex.EncodeToString(hash.Sum(nil))
+	staged.Size = size
+	return staged, nil
+}
+
+// validate는 tar.gz의 모든 항목이 컨텍스트 디렉터리 안에 풀리는 일반 파일/디렉터리/링크인지 확인하고
+// 최상위에 Dockerfile이 있는지 반환합니다
+func (s *ContextStore) validate(archive string) (bool, error) {
+	file, err := os.Open(archive)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return false, fmt.Errorf("%w: not a gzip file", ErrInvalidContext)
+	}
+	defer gz.Close()
+
+	hasDockerfile := false
+	var extracted int64
+	tr := tar.NewReader(gz)
+	for entries := 0; ; entries++ {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return hasDockerfile, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", ErrInvalidContext, err)
+		}
+		if entries >= contextMaxEntries {
+			return false, fmt.Errorf("%w: more than %d entries", ErrContextTooLarge, contextMaxEntries)
+		}
+
+		name, ok := contextPath(header.Name)
+		if !ok {
+			return false, fmt.Errorf("%w: entry %q escapes the context directory", ErrInvalidContext, header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg, tar.TypeDir:
+		case tar.TypeSymlink:
+			target := header.Linkname
+			if !path.IsAbs(target) {
+				target = path.Join(path.Dir(name), target)
+			}
+			if _, ok := contextPath(target); !ok {
+				return false, fmt.Errorf("%w: symlink %q points outside the context directory", ErrInvalidContext, header.Name)
+			}
+		case tar.TypeLink:
+			if _, ok := contextPath(header.Linkname); !ok {
+				return false, fmt.Errorf("%w: hard link %q points outside the context directory", ErrInvalidContext, header.Name)
+			}
+		case tar.TypeXGlobalHeader:
+			continue
+		default:
+			return false, fmt.Errorf("%w: entry %q has unsupported type %q", ErrInvalidContext, header.Name, header.Typeflag)
+		}
+
+		extracted += header.Size
+		if extracted > s.maxExtractedSize {
+			return false, fmt.Errorf("%w: exceeds %d bytes when extracted", ErrContextTooLarge, s.maxExtractedSize)
+		}
+		if name == "Dockerfile" && header.Typeflag == tar.TypeReg {
+			hasDockerfile = true
+		}
+	}
+}
+
+// contextPath는 tar 항목 이름을 컨텍스트 디렉터리 기준 경로로 정리합니다
+// 절대 경로이거나 ..로 디렉터리 밖을 가리키면 false를 반환합니다
+func contextPath(name string) (string, bool) {
+	if name == "" || path.IsAbs(name) || strings.Contains(name, `\`) {
+		return "", false
+	}
+	cleaned := path.Clean(name)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", false
+	}
+	return cleaned, true
+}
+
+// Commit은 검증된 컨텍스트를 jobID로 옮기고 빌드 Pod가 내려받을 때 사용할 토큰을 발급합니다
+func (s *ContextStore) Commit(staged *StagedContext, jobID string) (string, error) {
+	archive, tokenFile, ok := s.paths(jobID)
+	if !ok {
+		return "", fmt.Errorf("invalid job id %q", jobID)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(secret)
+
+	if err := os.WriteFile(tokenFile, []byte(token), 0o600); err != nil {
+		return "", err
+	}
+	if err := os.Rename(staged.path, archive); err != nil {
+		os.Remove(tokenFile)
+		return "", err
+	}
+	return token, nil
+}
+
+// Discard는 등록되지 않은 컨텍스트의 임시 파일을 삭제합니다
+func (s *ContextStore) Discard(staged *StagedContext) {
+	os.Remove(staged.path)
+}
+
+// Open은 token이 일치하면 jobID의 컨텍스트를 엽니다
+// 컨텍스트가 없거나 토큰이 다르면 os.ErrNotExist를 반환합니다
+func (s *ContextStore) Open(jobID, token string) (*os.File, error) {
+	archive, tokenFile, ok := s.paths(jobID)
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+
+	expected, err := os.ReadFile(tokenFile)
+	if err != nil || subtle.ConstantTimeCompare(expected, []byte(token)) != 1 {
+		return nil, os.ErrNotExist
+	}
+	return os.Open(archive)
+}
+
+// Delete는 jobID의 컨텍스트를 삭제합니다
+func (s *ContextStore) Delete(jobID string) {
+	archive, tokenFile, ok := s.paths(jobID)
+	if !ok {
+		return
+	}
+	os.Remove(archive)
+	os.Remove(tokenFile)
+}
+
+// JobIDs는 컨텍스트를 보관 중인 Job ID 목록을 반환합니다
+func (s *ContextStore) JobIDs() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(s.dir, "*.tar.gz"))
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, strings.TrimSuffix(filepath.Base(match), ".tar.gz"))
+	}
+	return ids, nil
+}
+
+// paths는 jobID의 컨텍스트 파일과 토큰 파일 경로를 반환합니다
+// 디렉터리 밖을 가리킬 수 있는 ID이면 false를 반환합니다
+func (s *ContextStore) paths(jobID string) (string, string, bool) {
+	if jobID == "" || !filepath.IsLocal(jobID) || strings.ContainsAny(jobID, `/\`) {
+		return "", "", false
+	}
+	base := filepath.Join(s.dir, jobID)
+	return base + ".tar.gz", base + ".token", true
+}
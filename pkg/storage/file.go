@@ -0,0 +1,589 @@
+package storage
+
+import (
+	"api-server/pkg/models"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSegmentSize는 로그 세그먼트 파일의 기본 최대 크기입니다
+	DefaultSegmentSize = 8 * 1024 * 1024
+	// DefaultMaxOpenSegments는 동시에 열어 두는 쓰기 세그먼트 파일의 기본 최대 개수입니다
+	DefaultMaxOpenSegments = 128
+
+	// fileIndexName은 세그먼트 목록을 담는 인덱스 파일 이름입니다
+	fileIndexName = "index.json"
+	// fileStatusName은 Job 상태를 담는 파일 이름입니다
+	fileStatusName = "status.json"
+)
+
+// FileStorage는 로컬 디스크 기반 로그 저장소 구현입니다
+//
+// Job마다 디렉터리를 만들고 로그를 NDJSON 세그먼트 파일에 추가만 합니다
+//
+//	<dir>/<job_id>/index.json       세그먼트 목록 (세그먼트별 첫 Seq)
+//	<dir>/<job_id>/<first_seq>.log  로그 세그먼트
+//	<dir>/<job_id>/status.json      현재 상태
+//
+// 세그먼트가 segmentSize를 넘으면 새 세그먼트를 시작하며,
+// since_seq 조회 시 인덱스로 시작 세그먼트를 찾아 그 이후만 읽습니다
+// 쓰기 중인 세그먼트 파일은 maxOpenSegments개까지만 열어 두고,
+// 넘으면 가장 오래 쓰지 않은 파일을 닫았다가 다음 로그를 쓸 때 다시 엽니다
+type FileStorage struct {
+	dir             string
+	segmentSize     int64
+	maxOpenSegments int
+
+	mu   sync.Mutex
+	jobs map[string]*fileJob
+	// open은 세그먼트 파일이 열려 있는 Job입니다
+	open     map[string]*fileJob
+	notifier *notifier
+}
+
+// fileJob은 디스크에서 읽어 캐시한 Job별 저장 상태입니다
+type fileJob struct {
+	dir      string
+	segments []fileSegment
+	lastSeq  int64
+	state    *models.JobState
+
+	// size와 file은 마지막(쓰기 중인) 세그먼트의 크기와 열린 파일입니다
+	size int64
+	file *os.File
+	// lastWrite는 마지막으로 로그를 쓴 시각입니다
+	lastWrite time.Time
+}
+
+// fileSegment는 인덱스에 기록되는 세그먼트 정보입니다
+type fileSegment struct {
+	FirstSeq int64  `json:"first_seq"`
+	File     string `json:"file"`
+}
+
+// fileIndex는 index.json의 구조입니다
+type fileIndex struct {
+	Segments []fileSegment `json:"segments"`
+}
+
+// FileStorageOption은 FileStorage의 선택적 설정입니다
+type FileStorageOption func(*FileStorage)
+
+// WithSegmentSize는 세그먼트 파일의 최대 크기를 설정합니다
+func WithSegmentSize(size int64) FileStorageOption {
+	return func(s *FileStorage) {
+		s.segmentSize = size
+	}
+}
+
+// WithMaxOpenSegments는 동시에 열어 두는 쓰기 세그먼트 파일의 최대 개수를 설정합니다
+func WithMaxOpenSegments(n int) FileStorageOption {
+	return func(s *FileStorage) {
+		s.maxOpenSegments = n
+	}
+}
+
+// NewFileStorage는 dir 아래에 로그를 저장하는 디스크 저장소를 생성합니다
+// 이전에 저장된 로그는 처음 조회될 때 디스크에서 읽어옵니다
+func NewFileStorage(dir string, opts ...FileStorageOption) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory %s: %w", dir, err)
+	}
+
+	s := &FileStorage{
+		dir:             dir,
+		segmentSize:     DefaultSegmentSize,
+		maxOpenSegments: DefaultMaxOpenSegments,
+		jobs:            make(map[string]*fileJob),
+		open:            make(map[string]*fileJob),
+		notifier:        newNotifier(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// SaveLog는 새로운 로그를 현재 시각으로 마지막 세그먼트에 추가합니다
+func (s *FileStorage) SaveLog(jobID, container, message, level string) {
+	s.SaveLogAt(jobID, container, message, level, time.Now().Truncate(time.Second))
+}
+
+// SaveLogAt은 새로운 로그를 주어진 시각으로 마지막 세그먼트에 추가합니다
+func (s *FileStorage) SaveLogAt(jobID, container, message, level string, timestamp time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, err := s.loadOrCreate(jobID)
+	if err != nil {
+		log.Printf("failed to save log of %s: %v", jobID, err)
+		return
+	}
+
+	entry := models.LogEntry{
+		Seq:       job.lastSeq + 1,
+		Timestamp: formatLogTimestamp(timestamp),
+		Container: container,
+		Message:   message,
+		Level:     level,
+	}
+	line, _ := json.Marshal(entry)
+	line = append(line, '\n')
+
+	if job.file == nil && len(s.open) >= s.maxOpenSegments {
+		s.closeLeastRecentlyWritten()
+	}
+	err = job.append(entry.Seq, line, s.segmentSize)
+	if job.file != nil {
+		job.lastWrite = time.Now()
+		s.open[jobID] = job
+	}
+	if err != nil {
+		log.Printf("failed to save log of %s: %v", jobID, err)
+		return
+	}
+	job.lastSeq = entry.Seq
+	s.notifier.notify(jobID)
+}
+
+// GetLogs는 특정 Job의 모든 로그를 조회합니다
+func (s *FileStorage) GetLogs(jobID string) ([]models.LogEntry, bool) {
+	return s.QueryLogs(jobID, LogQuery{})
+}
+
+// QueryLogs는 특정 Job의 로그 중 조건에 맞는 로그를 Seq 순으로 조회합니다
+func (s *FileStorage) QueryLogs(jobID string, query LogQuery) ([]models.LogEntry, bool) {
+	logs := []models.LogEntry{}
+	collect := func(entry models.LogEntry) error {
+		logs = append(logs, entry)
+		return nil
+	}
+
+	if query.Tail > 0 {
+		// Tail은 끝에서부터 세어야 하므로 since_seq 이후를 모두 읽은 뒤 적용
+		exists, err := s.read(jobID, query.SinceSeq, func(entry models.LogEntry) (bool, error) {
+			return true, collect(entry)
+		})
+		if err != nil {
+			log.Printf("failed to read logs of %s: %v", jobID, err)
+		}
+		return query.apply(logs), exists
+	}
+
+	exists, err := s.read(jobID, query.SinceSeq, query.visitor(collect))
+	if err != nil {
+		log.Printf("failed to read logs of %s: %v", jobID, err)
+	}
+	return logs, exists
+}
+
+// StreamLogs는 조건에 맞는 로그를 세그먼트 파일에서 읽는 대로 fn에 전달합니다
+func (s *FileStorage) StreamLogs(jobID string, query LogQuery, fn func(models.LogEntry) error) (bool, error) {
+	if query.Tail > 0 {
+		logs, exists := s.QueryLogs(jobID, query)
+		for _, entry := range logs {
+			if err := fn(entry); err != nil {
+				return exists, err
+			}
+		}
+		return exists, nil
+	}
+	return s.read(jobID, query.SinceSeq, query.visitor(fn))
+}
+
+// DeleteLogs는 특정 Job의 로그와 상태를 삭제합니다
+func (s *FileStorage) DeleteLogs(jobID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if job, exists := s.jobs[jobID]; exists {
+		job.close()
+		delete(s.jobs, jobID)
+		delete(s.open, jobID)
+	}
+	if dir, ok := s.jobDir(jobID); ok {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Printf("failed to delete logs of %s: %v", jobID, err)
+		}
+	}
+	s.notifier.notify(jobID)
+}
+
+// Exists는 특정 Job의 로그가 존재하는지 확인합니다
+func (s *FileStorage) Exists(jobID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, err := s.load(jobID)
+	return err == nil && job != nil && job.lastSeq > 0
+}
+
+// SaveStatus는 특정 Job의 상태를 저장합니다
+// 종료 상태가 되면 더 이상 쓰지 않을 세그먼트 파일을 닫습니다
+func (s *FileStorage) SaveStatus(jobID string, status models.JobStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, err := s.loadOrCreate(jobID)
+	if err != nil {
+		log.Printf("failed to save status of %s: %v", jobID, err)
+		return
+	}
+
+	state := models.JobState{
+		Status:    status,
+		UpdatedAt: time.Now().Format(time.RFC3339),
+	}
+	data, _ := json.Marshal(state)
+	if err := writeFileAtomic(filepath.Join(job.dir, fileStatusName), data); err != nil {
+		log.Printf("failed to save status of %s: %v", jobID, err)
+		return
+	}
+	job.state = &state
+
+	if status.IsTerminal() {
+		job.close()
+		delete(s.open, jobID)
+	}
+	s.notifier.notify(jobID)
+}
+
+// GetStatus는 특정 Job의 상태를 조회합니다
+func (s *FileStorage) GetStatus(jobID string) (models.JobState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, err := s.load(jobID)
+	if err != nil || job == nil || job.state == nil {
+		return models.JobState{}, false
+	}
+	return *job.state, true
+}
+
+// Subscribe는 특정 Job의 로그나 상태가 변경될 때 신호를 받는 채널을 반환합니다
+func (s *FileStorage) Subscribe(jobID string) (<-chan struct{}, func()) {
+	return s.notifier.subscribe(jobID)
+}
+
+// Close는 열려 있는 세그먼트 파일을 모두 닫습니다
+func (s *FileStorage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, job := range s.jobs {
+		job.close()
+	}
+	clear(s.open)
+	return nil
+}
+
+// closeLeastRecentlyWritten은 열려 있는 세그먼트 파일 중 가장 오래 쓰지 않은 파일을 닫습니다 (s.mu를 잡은 상태에서 호출)
+// 종료 상태가 되지 않은 채 남은 Job이 파일 디스크립터를 계속 차지하지 않도록 합니다
+func (s *FileStorage) closeLeastRecentlyWritten() {
+	var victim string
+	for jobID, job := range s.open {
+		if victim == "" || job.lastWrite.Before(s.open[victim].lastWrite) {
+			victim = jobID
+		}
+	}
+	if job, exists := s.open[victim]; exists {
+		job.close()
+		delete(s.open, victim)
+	}
+}
+
+// read는 since 이후의 로그를 Seq 순으로 visit에 전달합니다
+// 세그먼트 목록과 마지막 Seq만 잠금 상태에서 복사하고 파일은 잠금 없이 읽으므로
+// 읽는 동안 추가되는 로그는 포함되지 않습니다
+func (s *FileStorage) read(jobID string, since int64, visit func(models.LogEntry) (bool, error)) (bool, error) {
+	s.mu.Lock()
+	job, err := s.load(jobID)
+	if err != nil || job == nil || job.lastSeq == 0 {
+		s.mu.Unlock()
+		return false, err
+	}
+	dir, lastSeq := job.dir, job.lastSeq
+	segments := append([]fileSegment(nil), job.segments...)
+	s.mu.Unlock()
+
+	// since 다음 Seq를 포함하는 세그먼트부터 읽음
+	start := sort.Search(len(segments), func(i int) bool {
+		return segments[i].FirstSeq > since+1
+	}) - 1
+	if start < 0 {
+		start = 0
+	}
+
+	for _, segment := range segments[start:] {
+		more, err := readSegment(filepath.Join(dir, segment.File), func(entry models.LogEntry) (bool, error) {
+			if entry.Seq > lastSeq {
+				return false, nil
+			}
+			if entry.Seq <= since {
+				return true, nil
+			}
+			return visit(entry)
+		})
+		if err != nil {
+			// 조회 중에 삭제된 Job은 없는 것으로 처리
+			if errors.Is(err, os.ErrNotExist) {
+				return false, nil
+			}
+			return true, err
+		}
+		if !more {
+			break
+		}
+	}
+	return true, nil
+}
+
+// load는 캐시 또는 디스크에서 Job을 읽어옵니다 (s.mu를 잡은 상태에서 호출)
+// 저장된 적이 없는 Job이면 nil을 반환합니다
+func (s *FileStorage) load(jobID string) (*fileJob, error) {
+	if job, exists := s.jobs[jobID]; exists {
+		return job, nil
+	}
+
+	dir, ok := s.jobDir(jobID)
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, fileIndexName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var index fileIndex
+	if err := json.Unmarshal(data, &index); err != nil || len(index.Segments) == 0 {
+		return nil, fmt.Errorf("corrupted index of %s: %v", jobID, err)
+	}
+
+	job := &fileJob{dir: dir, segments: index.Segments}
+	if err := job.recover(); err != nil {
+		return nil, err
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, fileStatusName)); err == nil {
+		var state models.JobState
+		if json.Unmarshal(data, &state) == nil {
+			job.state = &state
+		}
+	}
+
+	s.jobs[jobID] = job
+	return job, nil
+}
+
+// loadOrCreate는 Job을 읽어오고 없으면 첫 세그먼트와 인덱스를 만듭니다
+func (s *FileStorage) loadOrCreate(jobID string) (*fileJob, error) {
+	job, err := s.load(jobID)
+	if err != nil || job != nil {
+		return job, err
+	}
+
+	dir, ok := s.jobDir(jobID)
+	if !ok {
+		return nil, fmt.Errorf("invalid job id %q", jobID)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	job = &fileJob{dir: dir, segments: []fileSegment{newFileSegment(1)}}
+	if err := job.writeIndex(); err != nil {
+		return nil, err
+	}
+
+	s.jobs[jobID] = job
+	return job, nil
+}
+
+// JobIDs는 저장소 디렉터리 아래의 모든 Job ID를 반환합니다
+// 재시작 전에 저장된 Job도 포함됩니다
+func (s *FileStorage) JobIDs() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		if _, ok := s.jobDir(entry.Name()); ok && entry.IsDir() {
+			ids = append(ids, entry.Name())
+		}
+	}
+	return ids, nil
+}
+
+// jobDir은 Job 디렉터리 경로를 반환합니다
+// 경로 구분자나 ..가 포함된 ID는 저장소 밖을 가리킬 수 있으므로 거부합니다
+func (s *FileStorage) jobDir(jobID string) (string, bool) {
+	if jobID == "" || !filepath.IsLocal(jobID) || strings.ContainsAny(jobID, `/\`) {
+		return "", false
+	}
+	return filepath.Join(s.dir, jobID), true
+}
+
+// append는 로그 한 줄을 마지막 세그먼트에 추가하고, 크기를 넘으면 새 세그먼트를 시작합니다
+func (j *fileJob) append(seq int64, line []byte, segmentSize int64) error {
+	if j.size > 0 && j.size+int64(len(line)) > segmentSize {
+		j.close()
+		j.segments = append(j.segments, newFileSegment(seq))
+		if err := j.writeIndex(); err != nil {
+			j.segments = j.segments[:len(j.segments)-1]
+			return err
+		}
+		j.size = 0
+	}
+
+	if j.file == nil {
+		file, err := os.OpenFile(j.lastSegmentPath(), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return err
+		}
+		j.file = file
+	}
+
+	n, err := j.file.Write(line)
+	j.size += int64(n)
+	return err
+}
+
+// recover는 마지막 세그먼트에서 마지막 Seq와 크기를 복구합니다
+// 비정상 종료로 마지막 줄이 잘렸으면 온전한 줄까지만 남기고 잘라냅니다
+func (j *fileJob) recover() error {
+	path := j.lastSegmentPath()
+
+	var valid int64
+	err := readSegmentLines(path, func(line []byte, entry models.LogEntry, ok bool) bool {
+		if !ok {
+			return false
+		}
+		valid += int64(len(line)) + 1
+		j.lastSeq = entry.Seq
+		return true
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		// 첫 로그가 기록되기 전에 종료된 경우
+		j.lastSeq = j.segments[len(j.segments)-1].FirstSeq - 1
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Size() > valid {
+		if err := os.Truncate(path, valid); err != nil {
+			return err
+		}
+	}
+	if j.lastSeq == 0 {
+		j.lastSeq = j.segments[len(j.segments)-1].FirstSeq - 1
+	}
+	j.size = valid
+	return nil
+}
+
+// writeIndex는 세그먼트 목록을 index.json에 기록합니다
+func (j *fileJob) writeIndex() error {
+	data, _ := json.Marshal(fileIndex{Segments: j.segments})
+	return writeFileAtomic(filepath.Join(j.dir, fileIndexName), data)
+}
+
+// lastSegmentPath는 쓰기 중인 마지막 세그먼트 파일 경로를 반환합니다
+func (j *fileJob) lastSegmentPath() string {
+	return filepath.Join(j.dir, j.segments[len(j.segments)-1].File)
+}
+
+// close는 열려 있는 세그먼트 파일을 닫습니다
+func (j *fileJob) close() {
+	if j.file != nil {
+		j.file.Close()
+		j.file = nil
+	}
+}
+
+// newFileSegment는 firstSeq부터 시작하는 세그먼트 정보를 생성합니다
+func newFileSegment(firstSeq int64) fileSegment {
+	return fileSegment{FirstSeq: firstSeq, File: fmt.Sprintf("%020d.log", firstSeq)}
+}
+
+// readSegment는 세그먼트 파일의 로그를 순서대로 visit에 전달합니다
+// 기록 중이거나 잘린 마지막 줄에서 멈추며, visit이 false를 반환하면 false를 반환합니다
+func readSegment(path string, visit func(models.LogEntry) (bool, error)) (bool, error) {
+	var visitErr error
+	more := true
+	err := readSegmentLines(path, func(_ []byte, entry models.LogEntry, ok bool) bool {
+		if !ok {
+			return false
+		}
+		more, visitErr = visit(entry)
+		return more && visitErr == nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return more, visitErr
+}
+
+// readSegmentLines는 세그먼트 파일을 한 줄씩 읽어 fn에 전달합니다
+// ok는 줄이 완전한 로그 항목인지 여부이며, fn이 false를 반환하면 중단합니다
+func readSegmentLines(path string, fn func(line []byte, entry models.LogEntry, ok bool) bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReaderSize(file, 64*1024)
+	for {
+		line, err := reader.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			// 긴 줄은 나머지를 이어서 읽음
+			rest, restErr := reader.ReadBytes('\n')
+			line, err = append(append([]byte(nil), line...), rest...), restErr
+		}
+		if err == io.EOF {
+			if len(line) > 0 {
+				// 개행이 없는 마지막 줄은 기록 중이거나 잘린 줄
+				fn(line, models.LogEntry{}, false)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		line = line[:len(line)-1]
+		var entry models.LogEntry
+		ok := json.Unmarshal(line, &entry) == nil
+		if !fn(line, entry, ok) {
+			return nil
+		}
+	}
+}
+
+// writeFileAtomic은 임시 파일에 쓴 뒤 이름을 바꿔 파일을 원자적으로 교체합니다
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
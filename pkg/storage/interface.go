@@ -1,18 +1,100 @@
 package storage
 
-import "api-server/pkg/models"
+import (
+	"api-server/pkg/models"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrDuplicateIdempotencyKey는 같은 Idempotency-Key로 등록된 Job이 이미 있을 때 반환됩니다
+	ErrDuplicateIdempotencyKey = errors.New("job with the same idempotency key already exists")
+
+	// ErrActiveJobName은 같은 이름의 Job이 아직 종료되지 않았을 때 반환됩니다
+	ErrActiveJobName = errors.New("job with the same name is still active")
+)
 
 // LogStorage는 로그 저장소 인터페이스입니다
 type LogStorage interface {
-	// SaveLog는 새로운 로그를 저장합니다
-	SaveLog(jobName, container, message, level string)
+	// SaveLog는 새로운 로그를 현재 시각(초 단위)으로 저장합니다
+	SaveLog(jobID, container, message, level string)
+
+	// SaveLogAt은 새로운 로그를 주어진 시각으로 저장합니다
+	// 수집한 컨테이너 로그의 Kubernetes 시각을 초 미만까지 보존하여 수집을 이어갈 위치로 사용합니다
+	SaveLogAt(jobID, container, message, level string, timestamp time.Time)
 
 	// GetLogs는 특정 Job의 모든 로그를 조회합니다
-	GetLogs(jobName string) ([]models.LogEntry, bool)
+	GetLogs(jobID string) ([]models.LogEntry, bool)
+
+	// QueryLogs는 특정 Job의 로그 중 조건에 맞는 로그를 Seq 순으로 조회합니다
+	QueryLogs(jobID string, query LogQuery) ([]models.LogEntry, bool)
+
+	// StreamLogs는 조건에 맞는 로그를 메모리에 모으지 않고 Seq 순으로 fn에 전달합니다
+	// Job이 없으면 false를, fn이 에러를 반환하면 중단하고 그 에러를 반환합니다
+	StreamLogs(jobID string, query LogQuery, fn func(models.LogEntry) error) (bool, error)
 
 	// DeleteLogs는 특정 Job의 로그를 삭제합니다
-	DeleteLogs(jobName string)
+	DeleteLogs(jobID string)
 
 	// Exists는 특정 Job의 로그가 존재하는지 확인합니다
-	Exists(jobName string) bool
+	Exists(jobID string) bool
+
+	// SaveStatus는 특정 Job의 상태를 저장합니다
+	SaveStatus(jobID string, status models.JobStatus)
+
+	// GetStatus는 특정 Job의 상태를 조회합니다
+	GetStatus(jobID string) (models.JobState, bool)
+
+	// Subscribe는 특정 Job의 로그나 상태가 변경될 때 신호를 받는 채널을 반환합니다
+	// 신호는 변경 내용을 담지 않으므로 수신 후 다시 조회해야 하며,
+	// 반환된 함수로 구독을 해제합니다
+	Subscribe(jobID string) (<-chan struct{}, func())
+}
+
+// StatusTransitioner는 상태 비교와 변경을 저장소에서 원자적으로 처리하는 LogStorage입니다
+// 여러 replica가 같은 저장소의 상태를 바꿀 수 있으면 프로세스 내 잠금 대신 이 인터페이스로 전이합니다
+type StatusTransitioner interface {
+	// TransitionStatus는 현재 상태가 from일 때만 상태를 to로 바꾸고 변경 로그를 함께 저장합니다
+	// 다른 프로세스가 먼저 상태를 바꿨으면 아무것도 저장하지 않고 false를 반환합니다
+	TransitionStatus(jobID string, from, to models.JobStatus, container, message, level string) (bool, error)
+}
+
+// JobLister는 로그가 저장된 Job을 나열할 수 있는 LogStorage입니다
+// Job 메타데이터를 메모리에 두는 구성에서는 재시작 전에 저장된 로그를 JobStore로 찾을 수 없으므로
+// LogReaper는 저장소가 이 인터페이스를 구현하면 저장소에 남은 Job의 보관 기간도 확인합니다
+type JobLister interface {
+	// JobIDs는 상태가 저장된 모든 Job ID를 반환합니다
+	JobIDs() ([]string, error)
+}
+
+// JobStore는 빌드 Job 메타데이터 저장소 인터페이스입니다
+type JobStore interface {
+	// SaveJob은 Job 메타데이터를 저장합니다
+	SaveJob(job models.Job)
+
+	// GetJob은 ID로 Job을 조회합니다
+	GetJob(id string) (models.Job, bool)
+
+	// FindByName은 같은 이름으로 가장 최근에 등록된 Job을 조회합니다
+	FindByName(name string) (models.Job, bool)
+
+	// FindByIdempotencyKey는 Idempotency-Key로 등록된 Job을 조회합니다
+	FindByIdempotencyKey(key string) (models.Job, bool)
+
+	// DeleteJob은 Job 메타데이터를 삭제합니다
+	DeleteJob(id string)
+
+	// ListJobs는 조건에 맞는 Job을 정렬하여 최대 query.Limit개 조회하고
+	// 다음 페이지가 있으면 다음 페이지 커서를 함께 반환합니다
+	ListJobs(query JobQuery) ([]models.Job, string, error)
+}
+
+// JobRegistrar는 Job 등록 시 중복 검사를 저장소에서 원자적으로 처리하는 JobStore입니다
+// 여러 replica가 같은 저장소를 공유하면 프로세스 내 잠금만으로는 중복 등록을 막을 수 없으므로
+// JobService는 저장소가 이 인터페이스를 구현하면 등록을 저장소에 맡깁니다
+type JobRegistrar interface {
+	// RegisterJob은 같은 Idempotency-Key의 Job과 종료되지 않은 같은 이름의 Job이 없을 때만
+	// Job을 저장하고 pending 상태로 만듭니다
+	// 충돌하면 기존 Job과 함께 ErrDuplicateIdempotencyKey 또는 ErrActiveJobName을 반환합니다
+	RegisterJob(job models.Job) (models.Job, error)
 }
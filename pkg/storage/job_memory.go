@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"api-server/pkg/models"
+	"sort"
+	"sync"
+)
+
+// MemoryJobStore는 메모리 기반 Job 메타데이터 저장소 구현입니다
+type MemoryJobStore struct {
+	mu       sync.RWMutex
+	jobs     map[string]models.Job
+	byName   map[string]string
+	byKey    map[string]string
+	statusOf StatusFunc
+}
+
+// NewMemoryJobStore는 새로운 메모리 Job 저장소를 생성합니다
+// statusOf는 목록 조회 시 상태 조건을 확인하는 데 사용됩니다
+func NewMemoryJobStore(statusOf StatusFunc) JobStore {
+	return &MemoryJobStore{
+		jobs:     make(map[string]models.Job),
+		byName:   make(map[string]string),
+		byKey:    make(map[string]string),
+		statusOf: statusOf,
+	}
+}
+
+// SaveJob은 Job 메타데이터를 저장합니다
+func (s *MemoryJobStore) SaveJob(job models.Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs[job.ID] = job
+	s.byName[job.Name] = job.ID
+	if job.IdempotencyKey != "" {
+		s.byKey[job.IdempotencyKey] = job.ID
+	}
+}
+
+// GetJob은 ID로 Job을 조회합니다
+func (s *MemoryJobStore) GetJob(id string) (models.Job, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, exists := s.jobs[id]
+	return job, exists
+}
+
+// FindByName은 같은 이름으로 가장 최근에 등록된 Job을 조회합니다
+func (s *MemoryJobStore) FindByName(name string) (models.Job, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, exists := s.jobs[s.byName[name]]
+	return job, exists
+}
+
+// FindByIdempotencyKey는 Idempotency-Key로 등록된 Job을 조회합니다
+func (s *MemoryJobStore) FindByIdempotencyKey(key string) (models.Job, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, exists := s.jobs[s.byKey[key]]
+	return job, exists
+}
+
+// DeleteJob은 Job 메타데이터를 삭제합니다
+func (s *MemoryJobStore) DeleteJob(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.jobs[id]
+	if !exists {
+		return
+	}
+
+	delete(s.jobs, id)
+	if s.byName[job.Name] == id {
+		delete(s.byName, job.Name)
+	}
+	if job.IdempotencyKey != "" && s.byKey[job.IdempotencyKey] == id {
+		delete(s.byKey, job.IdempotencyKey)
+	}
+}
+
+// ListJobs는 조건에 맞는 Job을 정렬하여 최대 query.Limit개 조회합니다
+func (s *MemoryJobStore) ListJobs(query JobQuery) ([]models.Job, string, error) {
+	var after *jobCursor
+	if query.Cursor != "" {
+		cursor, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = &cursor
+	}
+
+	s.mu.RLock()
+	matched := make([]models.Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		if !query.matches(job) {
+			continue
+		}
+		if after != nil && !query.less(after.Key, after.ID, query.sortKey(job), job.ID) {
+			continue
+		}
+		matched = append(matched, job)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		return query.less(query.sortKey(matched[i]), matched[i].ID, query.sortKey(matched[j]), matched[j].ID)
+	})
+
+	jobs := make([]models.Job, 0, query.Limit)
+	for _, job := range matched {
+		if len(query.Statuses) > 0 {
+			state, _ := s.statusOf(job.ID)
+			if !query.matchesStatus(state.Status) {
+				continue
+			}
+		}
+
+		// Limit보다 하나 더 있으면 다음 페이지가 존재
+		if len(jobs) == query.Limit {
+			return jobs, query.encodeCursor(jobs[len(jobs)-1]), nil
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, "", nil
+}
@@ -2,64 +2,303 @@ package storage
 
 import (
 	"api-server/pkg/models"
+	"fmt"
+	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// memoryEntryOverhead는 로그 한 줄의 필드 외 메모리 사용량 추정치입니다 (구조체와 슬라이스 헤더)
+const memoryEntryOverhead = 96
+
 // MemoryStorage는 메모리 기반 로그 저장소 구현입니다
+//
+// Job별 줄 수/바이트 상한을 넘으면 잘림 표시를 한 번 남기고 이후 빌드 출력은 버리며,
+// 전체 사용량이 memoryBudget을 넘으면 종료된 Job 중 가장 오래 조회되지 않은 Job의 로그부터 비웁니다
 type MemoryStorage struct {
-	mu    sync.RWMutex
-	logs  map[string][]models.LogEntry
+	mu       sync.RWMutex
+	logs     map[string][]models.LogEntry
+	statuses map[string]models.JobState
+	usage    map[string]*memoryUsage
+	notifier *notifier
+
+	maxLines     int
+	maxBytes     int64
+	memoryBudget int64
+	totalBytes   int64
+	overBudget   bool
+}
+
+// memoryUsage는 Job별 로그 메모리 사용량입니다
+type memoryUsage struct {
+	bytes     int64
+	truncated bool
+	evicted   bool
+	// lastAccess는 마지막으로 로그를 읽거나 쓴 시각(UnixNano)입니다 (읽기 잠금에서도 갱신)
+	lastAccess atomic.Int64
+}
+
+// touch는 마지막 접근 시각을 갱신합니다
+func (u *memoryUsage) touch() {
+	u.lastAccess.Store(time.Now().UnixNano())
+}
+
+// MemoryStorageOption은 MemoryStorage의 선택적 설정입니다
+type MemoryStorageOption func(*MemoryStorage)
+
+// WithMaxLinesPerJob은 Job별로 보관하는 최대 로그 줄 수를 설정합니다 (0이면 제한 없음)
+func WithMaxLinesPerJob(n int) MemoryStorageOption {
+	return func(s *MemoryStorage) {
+		s.maxLines = n
+	}
+}
+
+// WithMaxBytesPerJob은 Job별로 보관하는 최대 로그 크기를 설정합니다 (0이면 제한 없음)
+func WithMaxBytesPerJob(n int64) MemoryStorageOption {
+	return func(s *MemoryStorage) {
+		s.maxBytes = n
+	}
+}
+
+// WithMemoryBudget은 모든 Job의 로그가 사용할 수 있는 전체 메모리 크기를 설정합니다 (0이면 제한 없음)
+func WithMemoryBudget(n int64) MemoryStorageOption {
+	return func(s *MemoryStorage) {
+		s.memoryBudget = n
+	}
 }
 
 // NewMemoryStorage는 새로운 메모리 저장소를 생성합니다
-func NewMemoryStorage() LogStorage {
-	return &MemoryStorage{
-		logs: make(map[string][]models.LogEntry),
+func NewMemoryStorage(opts ...MemoryStorageOption) LogStorage {
+	s := &MemoryStorage{
+		logs:     make(map[string][]models.LogEntry),
+		statuses: make(map[string]models.JobState),
+		usage:    make(map[string]*memoryUsage),
+		notifier: newNotifier(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// SaveLog는 새로운 로그를 저장합니다
-func (s *MemoryStorage) SaveLog(jobName, container, message, level string) {
+// SaveLog는 새로운 로그를 현재 시각으로 저장합니다
+func (s *MemoryStorage) SaveLog(jobID, container, message, level string) {
+	s.SaveLogAt(jobID, container, message, level, time.Now().Truncate(time.Second))
+}
+
+// SaveLogAt은 새로운 로그를 주어진 시각으로 저장합니다
+// system 로그(상태 변경 등)는 Job별 상한을 넘어도 저장하여 빌드 결과를 확인할 수 있게 합니다
+func (s *MemoryStorage) SaveLogAt(jobID, container, message, level string, timestamp time.Time) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, exists := s.logs[jobName]; !exists {
-		s.logs[jobName] = []models.LogEntry{}
+	usage, exists := s.usage[jobID]
+	if !exists {
+		usage = &memoryUsage{}
+		s.usage[jobID] = usage
+		s.logs[jobID] = []models.LogEntry{}
 	}
+	usage.touch()
 
+	// Seq는 Job 내에서 1부터 증가하는 번호로, 재연결 시 이어받기 위치로 사용됨
 	entry := models.LogEntry{
-		Timestamp: time.Now().Format(time.RFC3339),
+		Seq:       s.nextSeq(jobID),
+		Timestamp: formatLogTimestamp(timestamp),
 		Container: container,
 		Message:   message,
 		Level:     level,
 	}
 
-	s.logs[jobName] = append(s.logs[jobName], entry)
+	if container != "system" && (usage.truncated || usage.evicted) {
+		return
+	}
+	if container != "system" && s.exceedsJobLimit(jobID, usage, entry) {
+		usage.truncated = true
+		entry.Container = "system"
+		entry.Level = "warn"
+		entry.Message = fmt.Sprintf("Log output truncated: job exceeded the limit of %s; further build output is dropped", s.jobLimit())
+	}
+
+	s.append(jobID, usage, entry)
+	s.evict()
+	s.notifier.notify(jobID)
+}
+
+// nextSeq는 jobID의 다음 Seq를 반환합니다
+func (s *MemoryStorage) nextSeq(jobID string) int64 {
+	logs := s.logs[jobID]
+	if len(logs) == 0 {
+		return 1
+	}
+	return logs[len(logs)-1].Seq + 1
+}
+
+// append는 로그를 추가하고 사용량에 반영합니다
+func (s *MemoryStorage) append(jobID string, usage *memoryUsage, entry models.LogEntry) {
+	size := entrySize(entry)
+	s.logs[jobID] = append(s.logs[jobID], entry)
+	usage.bytes += size
+	s.totalBytes += size
+}
+
+// exceedsJobLimit은 entry를 추가하면 Job별 상한을 넘는지 확인합니다 (잘림 표시 한 줄의 여유를 남김)
+func (s *MemoryStorage) exceedsJobLimit(jobID string, usage *memoryUsage, entry models.LogEntry) bool {
+	if s.maxLines > 0 && len(s.logs[jobID])+1 >= s.maxLines {
+		return true
+	}
+	return s.maxBytes > 0 && usage.bytes+entrySize(entry)+memoryEntryOverhead*2 > s.maxBytes
+}
+
+// jobLimit은 잘림 표시에 사용할 Job별 상한 설명입니다
+func (s *MemoryStorage) jobLimit() string {
+	switch {
+	case s.maxLines > 0 && s.maxBytes > 0:
+		return fmt.Sprintf("%d lines or %d bytes", s.maxLines, s.maxBytes)
+	case s.maxLines > 0:
+		return fmt.Sprintf("%d lines", s.maxLines)
+	default:
+		return fmt.Sprintf("%d bytes", s.maxBytes)
+	}
+}
+
+// evict는 전체 사용량이 memoryBudget 이하가 될 때까지 종료된 Job의 로그를 오래 조회되지 않은 순으로 비웁니다
+// 비운 Job에는 안내 로그 한 줄만 남기므로 Seq와 상태는 유지됩니다
+func (s *MemoryStorage) evict() {
+	if s.memoryBudget <= 0 {
+		return
+	}
+
+	for s.totalBytes > s.memoryBudget {
+		var victim string
+		var oldest int64
+		for jobID, usage := range s.usage {
+			if usage.evicted || !s.statuses[jobID].Status.IsTerminal() {
+				continue
+			}
+			if lastAccess := usage.lastAccess.Load(); victim == "" || lastAccess < oldest {
+				victim, oldest = jobID, lastAccess
+			}
+		}
+
+		if victim == "" {
+			if !s.overBudget {
+				log.Printf("log memory usage %d bytes exceeds budget %d bytes but no finished job can be evicted", s.totalBytes, s.memoryBudget)
+			}
+			s.overBudget = true
+			return
+		}
+
+		usage := s.usage[victim]
+		marker := models.LogEntry{
+			Seq:       s.nextSeq(victim),
+			Timestamp: time.Now().Format(time.RFC3339),
+			Container: "system",
+			Message:   "Logs were evicted from memory after the job finished",
+			Level:     "warn",
+		}
+		s.totalBytes -= usage.bytes
+		usage.bytes = 0
+		usage.evicted = true
+		s.logs[victim] = nil
+		s.append(victim, usage, marker)
+		s.notifier.notify(victim)
+	}
+	s.overBudget = false
+}
+
+// entrySize는 로그 한 줄의 메모리 사용량 추정치입니다
+func entrySize(entry models.LogEntry) int64 {
+	return int64(len(entry.Timestamp)+len(entry.Container)+len(entry.Message)+len(entry.Level)) + memoryEntryOverhead
 }
 
 // GetLogs는 특정 Job의 모든 로그를 조회합니다
-func (s *MemoryStorage) GetLogs(jobName string) ([]models.LogEntry, bool) {
+func (s *MemoryStorage) GetLogs(jobID string) ([]models.LogEntry, bool) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	logs, exists := s.logs[jobName]
+	logs, exists := s.logs[jobID]
+	if exists {
+		s.usage[jobID].touch()
+	}
 	return logs, exists
 }
 
+// QueryLogs는 특정 Job의 로그 중 조건에 맞는 로그를 Seq 순으로 조회합니다
+func (s *MemoryStorage) QueryLogs(jobID string, query LogQuery) ([]models.LogEntry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	logs, exists := s.logs[jobID]
+	if !exists {
+		return nil, false
+	}
+	s.usage[jobID].touch()
+	return query.apply(logs), true
+}
+
+// StreamLogs는 조건에 맞는 로그를 Seq 순으로 fn에 전달합니다
+// 로그는 추가만 되므로 조회 시점의 슬라이스를 잠금 없이 순회하여
+// 느린 클라이언트가 로그 저장을 막지 않도록 합니다
+func (s *MemoryStorage) StreamLogs(jobID string, query LogQuery, fn func(models.LogEntry) error) (bool, error) {
+	s.mu.RLock()
+	logs, exists := s.logs[jobID]
+	if exists {
+		s.usage[jobID].touch()
+	}
+	s.mu.RUnlock()
+
+	if !exists {
+		return false, nil
+	}
+	return true, query.each(logs, fn)
+}
+
 // DeleteLogs는 특정 Job의 로그를 삭제합니다
-func (s *MemoryStorage) DeleteLogs(jobName string) {
+func (s *MemoryStorage) DeleteLogs(jobID string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	delete(s.logs, jobName)
+	if usage, exists := s.usage[jobID]; exists {
+		s.totalBytes -= usage.bytes
+	}
+	delete(s.logs, jobID)
+	delete(s.usage, jobID)
+	delete(s.statuses, jobID)
+	s.notifier.notify(jobID)
 }
 
 // Exists는 특정 Job의 로그가 존재하는지 확인합니다
-func (s *MemoryStorage) Exists(jobName string) bool {
+func (s *MemoryStorage) Exists(jobID string) bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	_, exists := s.logs[jobName]
+	_, exists := s.logs[jobID]
 	return exists
 }
+
+// SaveStatus는 특정 Job의 상태를 저장합니다
+func (s *MemoryStorage) SaveStatus(jobID string, status models.JobStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.statuses[jobID] = models.JobState{
+		Status:    status,
+		UpdatedAt: time.Now().Format(time.RFC3339),
+	}
+	s.notifier.notify(jobID)
+}
+
+// GetStatus는 특정 Job의 상태를 조회합니다
+func (s *MemoryStorage) GetStatus(jobID string) (models.JobState, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state, exists := s.statuses[jobID]
+	return state, exists
+}
+
+// Subscribe는 특정 Job의 로그나 상태가 변경될 때 신호를 받는 채널을 반환합니다
+func (s *MemoryStorage) Subscribe(jobID string) (<-chan struct{}, func()) {
+	return s.notifier.subscribe(jobID)
+}
@@ -0,0 +1,71 @@
+package storage
+
+import "sync"
+
+// notifier는 Job별 구독자에게 변경 신호를 전달합니다
+// 채널 버퍼가 1이므로 처리 중에 발생한 여러 변경은 하나의 신호로 합쳐집니다
+type notifier struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan struct{}]struct{}
+}
+
+// newNotifier는 새로운 notifier를 생성합니다
+func newNotifier() *notifier {
+	return &notifier{
+		subscribers: make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+// subscribe는 jobID의 변경 신호를 받는 채널과 구독 해제 함수를 반환합니다
+func (n *notifier) subscribe(jobID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	n.mu.Lock()
+	if n.subscribers[jobID] == nil {
+		n.subscribers[jobID] = make(map[chan struct{}]struct{})
+	}
+	n.subscribers[jobID][ch] = struct{}{}
+	n.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			n.mu.Lock()
+			defer n.mu.Unlock()
+
+			delete(n.subscribers[jobID], ch)
+			if len(n.subscribers[jobID]) == 0 {
+				delete(n.subscribers, jobID)
+			}
+		})
+	}
+}
+
+// notify는 jobID의 모든 구독자에게 신호를 보냅니다 (대기 중인 신호가 있으면 생략)
+func (n *notifier) notify(jobID string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for ch := range n.subscribers[jobID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// notifyAll은 모든 Job의 구독자에게 신호를 보냅니다
+// 변경 알림을 놓쳤을 수 있을 때 구독자가 저장소를 다시 읽도록 합니다
+func (n *notifier) notifyAll() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, subscribers := range n.subscribers {
+		for ch := range subscribers {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
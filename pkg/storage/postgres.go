@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+const (
+	// postgresChannel은 Job 변경을 알리는 LISTEN/NOTIFY 채널 이름입니다 (payload: Job ID)
+	postgresChannel = "api_server_jobs"
+	// postgresMigrationLockKey는 마이그레이션에 사용하는 advisory lock 키입니다
+	postgresMigrationLockKey = 7261535
+	// postgresJobNameLockSpace는 Job 이름별 advisory lock의 첫 번째 키입니다 (두 번째 키는 이름의 해시)
+	postgresJobNameLockSpace = 7261536
+	// postgresUniqueViolation은 unique 제약 위반 SQLSTATE입니다
+	postgresUniqueViolation = "23505"
+
+	// DefaultPostgresMaxConns는 replica 하나가 여는 최대 연결 수의 기본값입니다
+	DefaultPostgresMaxConns = 10
+	// postgresConnMaxLifetime은 연결을 재사용하는 최대 시간입니다 (DB 장애 조치 후 재연결되도록)
+	postgresConnMaxLifetime = 30 * time.Minute
+	// postgresConnMaxIdleTime은 사용되지 않는 연결을 유지하는 최대 시간입니다
+	postgresConnMaxIdleTime = 5 * time.Minute
+	// postgresConnectTimeout은 시작 시 연결 확인을 기다리는 최대 시간입니다
+	postgresConnectTimeout = 10 * time.Second
+	// postgresListenRetryInterval은 알림 수신 연결이 끊겼을 때 재연결 간격입니다
+	postgresListenRetryInterval = 5 * time.Second
+)
+
+// postgresDialect는 PostgreSQL 쿼리 형식입니다
+var postgresDialect = sqlDialect{
+	rebind:         rebindDollar,
+	contains:       "strpos(message, %s) > 0",
+	lockMigrations: fmt.Sprintf("SELECT pg_advisory_xact_lock(%d)", postgresMigrationLockKey),
+	notify:         fmt.Sprintf("SELECT pg_notify('%s', ?)", postgresChannel),
+	lockJobName:    fmt.Sprintf("SELECT pg_advisory_xact_lock(%d, hashtext(?))", postgresJobNameLockSpace),
+	isUniqueViolation: func(err error) bool {
+		var pgErr *pgconn.PgError
+		return errors.As(err, &pgErr) && pgErr.Code == postgresUniqueViolation
+	},
+}
+
+// PostgresOption은 PostgreSQL 연결 풀의 선택적 설정입니다
+type PostgresOption func(*sql.DB)
+
+// WithMaxConns는 연결 풀의 최대 연결 수를 설정합니다
+func WithMaxConns(n int) PostgresOption {
+	return func(db *sql.DB) {
+		db.SetMaxOpenConns(n)
+		db.SetMaxIdleConns(n)
+	}
+}
+
+// NewPostgresStorage는 dsn의 PostgreSQL 데이터베이스를 사용하는 저장소를 생성합니다
+//
+// 여러 replica가 같은 데이터베이스를 사용할 수 있도록 마이그레이션은 advisory lock으로 직렬화하고,
+// 로그와 상태 변경은 LISTEN/NOTIFY로 다른 replica의 구독자에게도 알립니다
+func NewPostgresStorage(dsn string, opts ...PostgresOption) (*SQLStorage, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres database: %w", err)
+	}
+	db.SetMaxOpenConns(DefaultPostgresMaxConns)
+	db.SetMaxIdleConns(DefaultPostgresMaxConns)
+	db.SetConnMaxLifetime(postgresConnMaxLifetime)
+	db.SetConnMaxIdleTime(postgresConnMaxIdleTime)
+	for _, opt := range opts {
+		opt(db)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresConnectTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+
+	s, err := newSQLStorage(db, postgresDialect)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	conn, err := listenPostgres(ctx, dsn)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	listenCtx, stop := context.WithCancel(context.Background())
+	s.stopListening = stop
+	go s.receiveNotifications(listenCtx, dsn, conn)
+
+	return s, nil
+}
+
+// listenPostgres는 변경 알림을 받을 전용 연결을 열고 LISTEN을 시작합니다
+func listenPostgres(ctx context.Context, dsn string) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres listener: %w", err)
+	}
+	if _, err := conn.Exec(ctx, "LISTEN "+postgresChannel); err != nil {
+		conn.Close(context.Background())
+		return nil, fmt.Errorf("failed to listen on %s: %w", postgresChannel, err)
+	}
+	return conn, nil
+}
+
+// receiveNotifications는 ctx가 종료될 때까지 알림을 받아 해당 Job의 구독자에게 전달합니다
+// 연결이 끊기면 다시 연결하고, 그 사이 놓친 알림이 있을 수 있으므로 모든 구독자에게 알립니다
+func (s *SQLStorage) receiveNotifications(ctx context.Context, dsn string, conn *pgx.Conn) {
+	for {
+		for conn != nil {
+			notification, err := conn.WaitForNotification(ctx)
+			if err != nil {
+				conn.Close(context.Background())
+				conn = nil
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("postgres listener disconnected: %v", err)
+				break
+			}
+			s.notifier.notify(notification.Payload)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(postgresListenRetryInterval):
+		}
+
+		var err error
+		if conn, err = listenPostgres(ctx, dsn); err != nil {
+			log.Printf("failed to reconnect postgres listener: %v", err)
+			continue
+		}
+		s.notifier.notifyAll()
+	}
+}
+
+// rebindDollar는 ? 자리표시자를 $1, $2, ... 형식으로 변환합니다
+func rebindDollar(query string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteByte('$')
+		b.WriteString(strconv.Itoa(n))
+	}
+	return b.String()
+}
@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"api-server/pkg/models"
+	"api-server/pkg/utils"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// SortByCreatedAt은 생성 시각 기준 정렬입니다 (Job ID가 ULID이므로 ID 순서와 같음)
+	SortByCreatedAt = "created_at"
+	// SortByName은 Job 이름 기준 정렬입니다
+	SortByName = "name"
+)
+
+// ErrInvalidCursor는 해석할 수 없는 페이지 커서가 주어졌을 때 반환됩니다
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// formatLogTimestamp는 로그 엔트리에 기록할 시각 문자열을 반환합니다
+// 초 미만 정밀도가 없는 시각은 RFC3339 형식과 같습니다
+func formatLogTimestamp(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// StatusFunc는 Job ID로 현재 상태를 조회하는 함수입니다
+type StatusFunc func(jobID string) (models.JobState, bool)
+
+// JobQuery는 Job 목록 조회 조건입니다
+type JobQuery struct {
+	Statuses      []models.JobStatus
+	NamePrefix    string
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	SortBy        string
+	Descending    bool
+	Cursor        string
+	Limit         int
+}
+
+// LogQuery는 로그 조회 조건입니다
+// SinceSeq 이후의 로그 중 필터에 맞는 로그를 대상으로,
+// Tail이 있으면 마지막 Tail개만, Limit이 있으면 앞에서부터 Limit개까지 조회합니다
+type LogQuery struct {
+	SinceSeq int64
+	Limit    int
+	Tail     int
+
+	// Containers가 비어 있지 않으면 해당 컨테이너의 로그만 조회합니다
+	Containers []string
+	// MinLevel이 있으면 그 이상 심각도의 로그만 조회합니다 (info < warn < error)
+	MinLevel string
+	// Contains가 있으면 메시지에 해당 문자열이 포함된 로그만 조회합니다
+	Contains string
+	// Pattern이 있으면 메시지가 정규식에 매칭되는 로그만 조회합니다
+	Pattern *regexp.Regexp
+	// Since, Until은 로그 시각 범위입니다 (Since 이상, Until 미만)
+	Since time.Time
+	Until time.Time
+}
+
+// filtered는 Seq 범위 외의 필터 조건이 있는지 확인합니다
+func (q LogQuery) filtered() bool {
+	return len(q.Containers) > 0 || q.MinLevel != "" || q.Contains != "" ||
+		q.Pattern != nil || !q.Since.IsZero() || !q.Until.IsZero()
+}
+
+// Matches는 로그가 필터 조건을 만족하는지 확인합니다 (Seq, Limit, Tail 제외)
+func (q LogQuery) Matches(entry models.LogEntry) bool {
+	if len(q.Containers) > 0 && !slices.Contains(q.Containers, entry.Container) {
+		return false
+	}
+	if q.MinLevel != "" && utils.LogLevelSeverity(entry.Level) < utils.LogLevelSeverity(q.MinLevel) {
+		return false
+	}
+	if q.Contains != "" && !strings.Contains(entry.Message, q.Contains) {
+		return false
+	}
+	if q.Pattern != nil && !q.Pattern.MatchString(entry.Message) {
+		return false
+	}
+
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		timestamp, err := time.Parse(time.RFC3339, entry.Timestamp)
+		if err != nil {
+			return false
+		}
+		if !q.Since.IsZero() && timestamp.Before(q.Since) {
+			return false
+		}
+		if !q.Until.IsZero() && !timestamp.Before(q.Until) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply는 Seq 순으로 정렬된 로그에 조건을 적용합니다
+// 필터가 없으면 원본의 부분 슬라이스를 반환하고, 있으면 매칭된 로그만 복사합니다
+func (q LogQuery) apply(logs []models.LogEntry) []models.LogEntry {
+	start := sort.Search(len(logs), func(i int) bool {
+		return logs[i].Seq > q.SinceSeq
+	})
+	logs = logs[start:]
+
+	if !q.filtered() {
+		if q.Tail > 0 && len(logs) > q.Tail {
+			logs = logs[len(logs)-q.Tail:]
+		}
+		if q.Limit > 0 && len(logs) > q.Limit {
+			logs = logs[:q.Limit]
+		}
+		return logs
+	}
+
+	matched := []models.LogEntry{}
+	if q.Tail > 0 {
+		// 뒤에서부터 Tail개를 찾은 뒤 순서를 되돌림
+		for i := len(logs) - 1; i >= 0 && len(matched) < q.Tail; i-- {
+			if q.Matches(logs[i]) {
+				matched = append(matched, logs[i])
+			}
+		}
+		slices.Reverse(matched)
+	} else {
+		for i := range logs {
+			if q.Limit > 0 && len(matched) == q.Limit {
+				break
+			}
+			if q.Matches(logs[i]) {
+				matched = append(matched, logs[i])
+			}
+		}
+	}
+
+	if q.Limit > 0 && len(matched) > q.Limit {
+		matched = matched[:q.Limit]
+	}
+	return matched
+}
+
+// each는 Seq 순으로 정렬된 로그 중 조건에 맞는 로그마다 fn을 호출합니다
+// Tail이 없으면 매칭된 로그를 모으지 않고 바로 전달하며, fn이 에러를 반환하면 중단합니다
+func (q LogQuery) each(logs []models.LogEntry, fn func(models.LogEntry) error) error {
+	if q.Tail > 0 {
+		for _, entry := range q.apply(logs) {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	start := sort.Search(len(logs), func(i int) bool {
+		return logs[i].Seq > q.SinceSeq
+	})
+
+	visit := q.visitor(fn)
+	for _, entry := range logs[start:] {
+		if more, err := visit(entry); !more || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visitor는 Seq 순으로 전달되는 로그에 Tail을 제외한 조건을 적용하여 fn을 호출하는 함수를 반환합니다
+// 반환된 함수는 Limit에 도달하거나 fn이 에러를 반환하면 false를 반환합니다
+func (q LogQuery) visitor(fn func(models.LogEntry) error) func(models.LogEntry) (bool, error) {
+	sent := 0
+	return func(entry models.LogEntry) (bool, error) {
+		if entry.Seq <= q.SinceSeq || !q.Matches(entry) {
+			return true, nil
+		}
+		if err := fn(entry); err != nil {
+			return false, err
+		}
+		sent++
+		return q.Limit <= 0 || sent < q.Limit, nil
+	}
+}
+
+// jobCursor는 마지막으로 반환한 Job의 정렬 키입니다 (keyset pagination)
+type jobCursor struct {
+	Key string `json:"k"`
+	ID  string `json:"id"`
+}
+
+// sortKey는 정렬 기준에 해당하는 Job의 값을 반환합니다
+func (q JobQuery) sortKey(job models.Job) string {
+	if q.SortBy == SortByName {
+		return job.Name
+	}
+	return job.ID
+}
+
+// less는 정렬 순서상 a가 b보다 앞에 오는지 확인합니다
+func (q JobQuery) less(aKey, aID, bKey, bID string) bool {
+	if aKey != bKey {
+		return (aKey < bKey) != q.Descending
+	}
+	return (aID < bID) != q.Descending
+}
+
+// matches는 상태를 제외한 조건(이름, 생성 시각)을 만족하는지 확인합니다
+func (q JobQuery) matches(job models.Job) bool {
+	if !strings.HasPrefix(job.Name, q.NamePrefix) {
+		return false
+	}
+
+	if !q.CreatedAfter.IsZero() || !q.CreatedBefore.IsZero() {
+		createdAt, err := time.Parse(time.RFC3339, job.CreatedAt)
+		if err != nil {
+			return false
+		}
+		if !q.CreatedAfter.IsZero() && createdAt.Before(q.CreatedAfter) {
+			return false
+		}
+		if !q.CreatedBefore.IsZero() && !createdAt.Before(q.CreatedBefore) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesStatus는 상태 조건을 만족하는지 확인합니다
+func (q JobQuery) matchesStatus(status models.JobStatus) bool {
+	if len(q.Statuses) == 0 {
+		return true
+	}
+	for _, s := range q.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeCursor는 Job의 정렬 키로 다음 페이지 커서를 생성합니다
+func (q JobQuery) encodeCursor(job models.Job) string {
+	data, _ := json.Marshal(jobCursor{Key: q.sortKey(job), ID: job.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor는 페이지 커서를 해석합니다
+func decodeCursor(cursor string) (jobCursor, error) {
+	var c jobCursor
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
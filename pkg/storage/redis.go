@@ -0,0 +1,396 @@
+package storage
+
+import (
+	"api-server/pkg/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// DefaultRedisKeyPrefix는 Redis 키와 pub/sub 채널 이름의 기본 접두사입니다
+	DefaultRedisKeyPrefix = "api-server:"
+
+	// redisPageSize는 XRANGE/XREVRANGE 한 번에 읽는 최대 로그 수입니다
+	redisPageSize = 500
+	// redisRetryInterval은 pub/sub 연결이 끊겼을 때 재시도 간격입니다
+	redisRetryInterval = time.Second
+)
+
+// redisGlobEscaper는 키 접두사를 SCAN MATCH 패턴에 문자 그대로 넣기 위해 glob 문자를 이스케이프합니다
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// redisSaveLog는 Job의 Seq를 발급하고 같은 번호를 ID로 로그를 스트림에 추가한 뒤 변경을 알립니다
+// 발급과 추가를 하나의 스크립트로 실행하므로 여러 replica가 동시에 저장해도 순서가 어긋나지 않습니다
+var redisSaveLog = redis.NewScript(`
+local seq = redis.call('INCR', KEYS[1])
+redis.call('XADD', KEYS[2], seq .. '-0',
+	'timestamp', ARGV[1], 'container', ARGV[2], 'message', ARGV[3], 'level', ARGV[4])
+redis.call('PUBLISH', ARGV[5], ARGV[6])
+return seq
+`)
+
+// redisTransitionStatus는 현재 상태가 ARGV[1]일 때만 상태를 ARGV[2]로 바꾸고 변경 로그를 추가합니다
+// 비교와 변경을 하나의 스크립트로 실행하므로 다른 replica의 상태 변경을 덮어쓰지 않습니다
+var redisTransitionStatus = redis.NewScript(`
+if redis.call('HGET', KEYS[3], 'status') ~= ARGV[1] then
+	return 0
+end
+local seq = redis.call('INCR', KEYS[1])
+redis.call('XADD', KEYS[2], seq .. '-0',
+	'timestamp', ARGV[3], 'container', ARGV[4], 'message', ARGV[5], 'level', ARGV[6])
+redis.call('HSET', KEYS[3], 'status', ARGV[2], 'updated_at', ARGV[3])
+redis.call('PUBLISH', ARGV[7], ARGV[8])
+return 1
+`)
+
+// RedisStorage는 Redis 스트림 기반 로그 저장소 구현입니다
+//
+// Job마다 다음 키를 사용하며, 스트림 엔트리 ID는 "<seq>-0"이므로 since_seq 조회는 XRANGE 한 번으로 처리됩니다
+//
+//	<prefix>{<job_id>}:seq     마지막으로 발급한 Seq
+//	<prefix>{<job_id>}:logs    로그 스트림
+//	<prefix>{<job_id>}:status  현재 상태 (hash)
+//
+// 로그와 상태 변경은 <prefix>events 채널로 발행되므로 같은 Redis를 구독하는 모든 프로세스에 전달됩니다
+// Job 메타데이터(JobStore)는 저장하지 않으므로 여러 replica가 공유하는 저장소로는 사용할 수 없습니다
+type RedisStorage struct {
+	client   *redis.Client
+	prefix   string
+	pubsub   *redis.PubSub
+	notifier *notifier
+	cancel   context.CancelFunc
+}
+
+// RedisStorageOption은 RedisStorage의 선택적 설정입니다
+type RedisStorageOption func(*RedisStorage)
+
+// WithKeyPrefix는 Redis 키와 채널 이름의 접두사를 설정합니다
+// 여러 api-server 배포가 같은 Redis를 사용할 때 구분하기 위해 사용합니다
+func WithKeyPrefix(prefix string) RedisStorageOption {
+	return func(s *RedisStorage) {
+		s.prefix = prefix
+	}
+}
+
+// NewRedisStorage는 url(redis://...)의 Redis를 사용하는 로그 저장소를 생성합니다
+// 변경 알림 채널 구독이 확인된 뒤에 반환하므로 이후의 변경은 놓치지 않습니다
+func NewRedisStorage(url string, opts ...RedisStorageOption) (*RedisStorage, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis url: %w", err)
+	}
+
+	s := &RedisStorage{
+		client:   redis.NewClient(options),
+		prefix:   DefaultRedisKeyPrefix,
+		notifier: newNotifier(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.pubsub = s.client.Subscribe(ctx, s.channel())
+	if _, err := s.pubsub.Receive(ctx); err != nil {
+		s.Close()
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", s.channel(), err)
+	}
+	go s.receive(ctx)
+
+	return s, nil
+}
+
+// Close는 변경 알림 구독을 중단하고 Redis 연결을 닫습니다
+func (s *RedisStorage) Close() error {
+	s.cancel()
+	s.pubsub.Close()
+	return s.client.Close()
+}
+
+// receive는 ctx가 종료될 때까지 변경 알림을 받아 해당 Job의 구독자에게 전달합니다
+func (s *RedisStorage) receive(ctx context.Context) {
+	for {
+		message, err := s.pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("redis subscription interrupted: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(redisRetryInterval):
+			}
+			continue
+		}
+
+		switch message := message.(type) {
+		case *redis.Subscription:
+			// 재연결 후 다시 구독된 경우 그 사이 놓친 알림이 있을 수 있음
+			s.notifier.notifyAll()
+		case *redis.Message:
+			s.notifier.notify(message.Payload)
+		}
+	}
+}
+
+// channel은 변경 알림 채널 이름입니다 (payload: Job ID)
+func (s *RedisStorage) channel() string {
+	return s.prefix + "events"
+}
+
+// key는 Job의 키 이름입니다
+// Job ID를 hash tag로 감싸 Redis Cluster에서도 한 Job의 키가 같은 slot에 놓이도록 합니다
+func (s *RedisStorage) key(jobID, name string) string {
+	return s.prefix + "{" + jobID + "}:" + name
+}
+
+// SaveLog는 새로운 로그를 현재 시각으로 Job의 스트림에 추가합니다
+func (s *RedisStorage) SaveLog(jobID, container, message, level string) {
+	s.SaveLogAt(jobID, container, message, level, time.Now().Truncate(time.Second))
+}
+
+// SaveLogAt은 새로운 로그를 주어진 시각으로 Job의 스트림에 추가합니다
+func (s *RedisStorage) SaveLogAt(jobID, container, message, level string, timestamp time.Time) {
+	ctx := context.Background()
+	keys := []string{s.key(jobID, "seq"), s.key(jobID, "logs")}
+	err := redisSaveLog.Run(ctx, s.client, keys,
+		formatLogTimestamp(timestamp), container, message, level, s.channel(), jobID).Err()
+	if err != nil {
+		log.Printf("failed to save log of %s: %v", jobID, err)
+		return
+	}
+	s.notifier.notify(jobID)
+}
+
+// GetLogs는 특정 Job의 모든 로그를 조회합니다
+func (s *RedisStorage) GetLogs(jobID string) ([]models.LogEntry, bool) {
+	return s.QueryLogs(jobID, LogQuery{})
+}
+
+// QueryLogs는 특정 Job의 로그 중 조건에 맞는 로그를 Seq 순으로 조회합니다
+func (s *RedisStorage) QueryLogs(jobID string, query LogQuery) ([]models.LogEntry, bool) {
+	logs := []models.LogEntry{}
+	exists, err := s.StreamLogs(jobID, query, func(entry models.LogEntry) error {
+		logs = append(logs, entry)
+		return nil
+	})
+	if err != nil {
+		log.Printf("failed to read logs of %s: %v", jobID, err)
+	}
+	return logs, exists
+}
+
+// StreamLogs는 조건에 맞는 로그를 스트림에서 페이지 단위로 읽으며 fn에 전달합니다
+func (s *RedisStorage) StreamLogs(jobID string, query LogQuery, fn func(models.LogEntry) error) (bool, error) {
+	ctx := context.Background()
+	stream := s.key(jobID, "logs")
+
+	if query.Tail > 0 {
+		return s.streamTail(ctx, jobID, query, fn)
+	}
+
+	found := false
+	visit := query.visitor(fn)
+	start := query.SinceSeq + 1
+	for {
+		messages, err := s.client.XRangeN(ctx, stream, streamID(start), "+", redisPageSize).Result()
+		if err != nil {
+			return found, err
+		}
+		for _, message := range messages {
+			found = true
+			entry, err := logEntry(message)
+			if err != nil {
+				return true, err
+			}
+			if more, err := visit(entry); !more || err != nil {
+				return true, err
+			}
+			start = entry.Seq + 1
+		}
+		if len(messages) < redisPageSize {
+			return found || s.Exists(jobID), nil
+		}
+	}
+}
+
+// streamTail은 스트림의 끝에서부터 조건에 맞는 로그를 Tail개 찾아 Seq 순으로 fn에 전달합니다
+func (s *RedisStorage) streamTail(ctx context.Context, jobID string, query LogQuery, fn func(models.LogEntry) error) (bool, error) {
+	stream := s.key(jobID, "logs")
+	first := streamID(query.SinceSeq + 1)
+
+	var tail []models.LogEntry
+	end := "+"
+	for len(tail) < query.Tail {
+		messages, err := s.client.XRevRangeN(ctx, stream, end, first, redisPageSize).Result()
+		if err != nil {
+			return false, err
+		}
+		for _, message := range messages {
+			entry, err := logEntry(message)
+			if err != nil {
+				return false, err
+			}
+			if query.Matches(entry) {
+				tail = append(tail, entry)
+				if len(tail) == query.Tail {
+					break
+				}
+			}
+			end = streamID(entry.Seq - 1)
+		}
+		if len(messages) < redisPageSize {
+			break
+		}
+	}
+	if len(tail) == 0 {
+		return s.Exists(jobID), nil
+	}
+
+	slices.Reverse(tail)
+	if query.Limit > 0 && len(tail) > query.Limit {
+		tail = tail[:query.Limit]
+	}
+	for _, entry := range tail {
+		if err := fn(entry); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// DeleteLogs는 특정 Job의 로그와 상태를 삭제합니다
+func (s *RedisStorage) DeleteLogs(jobID string) {
+	ctx := context.Background()
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.key(jobID, "seq"), s.key(jobID, "logs"), s.key(jobID, "status"))
+		pipe.Publish(ctx, s.channel(), jobID)
+		return nil
+	})
+	if err != nil {
+		log.Printf("failed to delete logs of %s: %v", jobID, err)
+	}
+	s.notifier.notify(jobID)
+}
+
+// Exists는 특정 Job의 로그가 존재하는지 확인합니다
+func (s *RedisStorage) Exists(jobID string) bool {
+	n, err := s.client.Exists(context.Background(), s.key(jobID, "logs")).Result()
+	if err != nil {
+		log.Printf("failed to check logs of %s: %v", jobID, err)
+		return false
+	}
+	return n > 0
+}
+
+// SaveStatus는 특정 Job의 상태를 저장합니다
+func (s *RedisStorage) SaveStatus(jobID string, status models.JobStatus) {
+	ctx := context.Background()
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, s.key(jobID, "status"),
+			"status", string(status),
+			"updated_at", time.Now().Format(time.RFC3339))
+		pipe.Publish(ctx, s.channel(), jobID)
+		return nil
+	})
+	if err != nil {
+		log.Printf("failed to save status of %s: %v", jobID, err)
+		return
+	}
+	s.notifier.notify(jobID)
+}
+
+// TransitionStatus는 현재 상태가 from일 때만 상태를 to로 바꾸고 변경 로그를 함께 추가합니다
+func (s *RedisStorage) TransitionStatus(jobID string, from, to models.JobStatus, container, message, level string) (bool, error) {
+	ctx := context.Background()
+	keys := []string{s.key(jobID, "seq"), s.key(jobID, "logs"), s.key(jobID, "status")}
+	changed, err := redisTransitionStatus.Run(ctx, s.client, keys,
+		string(from), string(to), time.Now().Format(time.RFC3339), container, message, level, s.channel(), jobID).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to change status of %s: %w", jobID, err)
+	}
+	if changed == 0 {
+		return false, nil
+	}
+	s.notifier.notify(jobID)
+	return true, nil
+}
+
+// GetStatus는 특정 Job의 상태를 조회합니다
+func (s *RedisStorage) GetStatus(jobID string) (models.JobState, bool) {
+	values, err := s.client.HGetAll(context.Background(), s.key(jobID, "status")).Result()
+	if err != nil {
+		log.Printf("failed to get status of %s: %v", jobID, err)
+		return models.JobState{}, false
+	}
+	if values["status"] == "" {
+		return models.JobState{}, false
+	}
+	return models.JobState{
+		Status:    models.JobStatus(values["status"]),
+		UpdatedAt: values["updated_at"],
+	}, true
+}
+
+// JobIDs는 상태 키가 있는 모든 Job ID를 반환합니다
+// SCAN으로 조회하므로 키가 많아도 Redis를 오래 막지 않습니다
+func (s *RedisStorage) JobIDs() ([]string, error) {
+	ctx := context.Background()
+	prefix, suffix := s.prefix+"{", "}:status"
+	pattern := redisGlobEscaper.Replace(prefix) + "*" + suffix
+
+	var ids []string
+	iter := s.client.Scan(ctx, 0, pattern, redisPageSize).Iterator()
+	for iter.Next(ctx) {
+		id := strings.TrimSuffix(strings.TrimPrefix(iter.Val(), prefix), suffix)
+		if id != "" && !strings.ContainsAny(id, "{}") {
+			ids = append(ids, id)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan job keys: %w", err)
+	}
+	return ids, nil
+}
+
+// Subscribe는 특정 Job의 로그나 상태가 변경될 때 신호를 받는 채널을 반환합니다
+// 다른 replica에서 저장한 변경도 pub/sub으로 전달됩니다
+func (s *RedisStorage) Subscribe(jobID string) (<-chan struct{}, func()) {
+	return s.notifier.subscribe(jobID)
+}
+
+// streamID는 Seq에 해당하는 스트림 엔트리 ID입니다
+func streamID(seq int64) string {
+	return strconv.FormatInt(seq, 10) + "-0"
+}
+
+// logEntry는 스트림 엔트리를 LogEntry로 변환합니다
+func logEntry(message redis.XMessage) (models.LogEntry, error) {
+	seq, _, _ := strings.Cut(message.ID, "-")
+	n, err := strconv.ParseInt(seq, 10, 64)
+	if err != nil {
+		return models.LogEntry{}, errors.New("unexpected stream entry id " + message.ID)
+	}
+
+	field := func(name string) string {
+		value, _ := message.Values[name].(string)
+		return value
+	}
+	return models.LogEntry{
+		Seq:       n,
+		Timestamp: field("timestamp"),
+		Container: field("container"),
+		Message:   field("message"),
+		Level:     field("level"),
+	}, nil
+}
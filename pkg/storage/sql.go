@@ -0,0 +1,689 @@
+package storage
+
+import (
+	"api-server/pkg/models"
+	"api-server/pkg/utils"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// sqlMigrations는 SQL 저장소의 스키마 변경 목록입니다 (인덱스+1이 버전)
+// 각 마이그레이션은 순서대로 실행할 문장 목록이며, 문장 안의 ';'는 그대로 전달됩니다
+// 적용된 마이그레이션은 수정하지 말고 새 항목을 추가해야 합니다
+var sqlMigrations = [][]string{
+	{
+		`CREATE TABLE jobs (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			image_name TEXT NOT NULL,
+			namespace TEXT NOT NULL,
+			created_at TEXT NOT NULL,
+			created_unix BIGINT NOT NULL,
+			idempotency_key TEXT,
+			request_hash TEXT NOT NULL
+		)`,
+		`CREATE INDEX jobs_name_id ON jobs (name, id)`,
+		`CREATE INDEX jobs_created_unix ON jobs (created_unix)`,
+		`CREATE INDEX jobs_idempotency_key ON jobs (idempotency_key)`,
+		`CREATE TABLE job_statuses (
+			job_id TEXT PRIMARY KEY,
+			status TEXT NOT NULL,
+			updated_at TEXT NOT NULL
+		)`,
+		`CREATE INDEX job_statuses_status ON job_statuses (status)`,
+		`CREATE TABLE log_heads (
+			job_id TEXT PRIMARY KEY,
+			last_seq BIGINT NOT NULL
+		)`,
+		`CREATE TABLE logs (
+			job_id TEXT NOT NULL,
+			seq BIGINT NOT NULL,
+			timestamp TEXT NOT NULL,
+			timestamp_unix BIGINT NOT NULL,
+			container TEXT NOT NULL,
+			message TEXT NOT NULL,
+			level TEXT NOT NULL,
+			PRIMARY KEY (job_id, seq)
+		)`,
+	},
+	{
+		`ALTER TABLE jobs ADD COLUMN git_url TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE jobs ADD COLUMN git_ref TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE jobs ADD COLUMN git_commit TEXT NOT NULL DEFAULT ''`,
+	},
+	{
+		// 여러 replica가 같은 키로 동시에 등록하지 못하도록 unique 인덱스로 교체 (기존 중복은 최신 Job에만 키를 남김)
+		`UPDATE jobs SET idempotency_key = NULL WHERE idempotency_key <> '' AND id < (
+			SELECT MAX(latest.id) FROM jobs latest WHERE latest.idempotency_key = jobs.idempotency_key
+		)`,
+		`DROP INDEX jobs_idempotency_key`,
+		`CREATE UNIQUE INDEX jobs_idempotency_key ON jobs (idempotency_key) WHERE idempotency_key <> ''`,
+	},
+}
+
+// errStatusChanged는 TransitionStatus 도중 다른 프로세스가 상태를 먼저 바꿨을 때 트랜잭션을 되돌리기 위한 에러입니다
+var errStatusChanged = errors.New("job status was changed concurrently")
+
+// sqlDialect는 SQL 데이터베이스별 차이입니다
+type sqlDialect struct {
+	// rebind는 ? 자리표시자를 데이터베이스 형식으로 변환합니다
+	rebind func(query string) string
+	// contains는 message에 인자 문자열이 포함되는지 확인하는 조건식 형식입니다
+	contains string
+	// lockMigrations는 여러 프로세스가 동시에 마이그레이션하지 않도록 트랜잭션 안에서 실행하는 잠금 쿼리입니다
+	lockMigrations string
+	// notify는 다른 프로세스에 Job 변경을 알리는 쿼리입니다 (인자: Job ID)
+	notify string
+	// lockJobName은 같은 이름의 Job 등록을 직렬화하기 위해 트랜잭션 안에서 실행하는 잠금 쿼리입니다 (인자: Job 이름)
+	// 비어 있으면 데이터베이스가 쓰기 트랜잭션을 직렬화한다고 봅니다
+	lockJobName string
+	// isUniqueViolation은 unique 제약 위반 에러인지 확인합니다
+	isUniqueViolation func(err error) bool
+}
+
+// SQLStorage는 SQL 데이터베이스 기반 로그/Job 저장소 구현입니다
+// LogStorage와 JobStore를 함께 구현하므로 상태 조건을 포함한 목록 조회도 DB에서 처리합니다
+type SQLStorage struct {
+	db       *sql.DB
+	dialect  sqlDialect
+	notifier *notifier
+
+	// stopListening은 다른 프로세스의 변경 알림 수신을 중단합니다 (수신하지 않으면 nil)
+	stopListening func()
+}
+
+// newSQLStorage는 마이그레이션을 적용한 뒤 SQL 저장소를 생성합니다
+func newSQLStorage(db *sql.DB, dialect sqlDialect) (*SQLStorage, error) {
+	s := &SQLStorage{
+		db:       db,
+		dialect:  dialect,
+		notifier: newNotifier(),
+	}
+	if err := s.migrate(); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return s, nil
+}
+
+// migrate는 적용되지 않은 마이그레이션을 순서대로 하나의 트랜잭션씩 적용합니다
+// 버전 확인과 적용을 같은 트랜잭션에서 잠금을 잡고 수행하므로 여러 replica가 동시에 시작해도 안전합니다
+func (s *SQLStorage) migrate() error {
+	err := s.inTx(func(tx *sql.Tx) error {
+		if err := s.lockMigrations(tx); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			applied_at TEXT NOT NULL
+		)`)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, migration := range sqlMigrations {
+		version := i + 1
+		err := s.inTx(func(tx *sql.Tx) error {
+			if err := s.lockMigrations(tx); err != nil {
+				return err
+			}
+
+			var applied int
+			if err := tx.QueryRow(s.dialect.rebind(`SELECT COUNT(*) FROM schema_migrations WHERE version = ?`), version).Scan(&applied); err != nil {
+				return err
+			}
+			if applied > 0 {
+				return nil
+			}
+
+			for _, statement := range migration {
+				if _, err := tx.Exec(statement); err != nil {
+					return err
+				}
+			}
+			_, err := tx.Exec(s.dialect.rebind(`INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`),
+				version, time.Now().Format(time.RFC3339))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// lockMigrations는 트랜잭션이 끝날 때까지 유지되는 마이그레이션 잠금을 잡습니다
+func (s *SQLStorage) lockMigrations(tx *sql.Tx) error {
+	if s.dialect.lockMigrations == "" {
+		return nil
+	}
+	_, err := tx.Exec(s.dialect.lockMigrations)
+	return err
+}
+
+// publish는 트랜잭션이 커밋되면 다른 프로세스에 Job 변경을 알리도록 예약합니다
+func (s *SQLStorage) publish(tx *sql.Tx, jobID string) error {
+	if s.dialect.notify == "" {
+		return nil
+	}
+	_, err := tx.Exec(s.dialect.rebind(s.dialect.notify), jobID)
+	return err
+}
+
+// Close는 변경 알림 수신을 중단하고 데이터베이스 연결을 닫습니다
+func (s *SQLStorage) Close() error {
+	if s.stopListening != nil {
+		s.stopListening()
+	}
+	return s.db.Close()
+}
+
+// exec는 쿼리를 실행하고 실패하면 로그를 남깁니다
+func (s *SQLStorage) exec(action, query string, args ...any) bool {
+	if _, err := s.db.Exec(s.dialect.rebind(query), args...); err != nil {
+		log.Printf("failed to %s: %v", action, err)
+		return false
+	}
+	return true
+}
+
+// SaveLog는 새로운 로그를 현재 시각으로 저장합니다
+func (s *SQLStorage) SaveLog(jobID, container, message, level string) {
+	s.SaveLogAt(jobID, container, message, level, time.Now().Truncate(time.Second))
+}
+
+// SaveLogAt은 새로운 로그를 주어진 시각으로 저장합니다
+// log_heads 행을 갱신하여 Job별 Seq를 발급하므로 동시에 저장해도 번호가 겹치지 않습니다
+func (s *SQLStorage) SaveLogAt(jobID, container, message, level string, timestamp time.Time) {
+	err := s.inTx(func(tx *sql.Tx) error {
+		if err := s.insertLog(tx, jobID, timestamp, container, message, level); err != nil {
+			return err
+		}
+		return s.publish(tx, jobID)
+	})
+	if err != nil {
+		log.Printf("failed to save log of %s: %v", jobID, err)
+		return
+	}
+	s.notifier.notify(jobID)
+}
+
+// insertLog는 Job의 다음 Seq를 발급하여 트랜잭션 안에서 로그를 추가합니다
+func (s *SQLStorage) insertLog(tx *sql.Tx, jobID string, now time.Time, container, message, level string) error {
+	var seq int64
+	err := tx.QueryRow(s.dialect.rebind(`INSERT INTO log_heads (job_id, last_seq) VALUES (?, 1)
+		ON CONFLICT (job_id) DO UPDATE SET last_seq = log_heads.last_seq + 1
+		RETURNING last_seq`), jobID).Scan(&seq)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(s.dialect.rebind(`INSERT INTO logs
+		(job_id, seq, timestamp, timestamp_unix, container, message, level)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`),
+		jobID, seq, formatLogTimestamp(now), now.Unix(), container, message, level)
+	return err
+}
+
+// GetLogs는 특정 Job의 모든 로그를 조회합니다
+func (s *SQLStorage) GetLogs(jobID string) ([]models.LogEntry, bool) {
+	return s.QueryLogs(jobID, LogQuery{})
+}
+
+// QueryLogs는 특정 Job의 로그 중 조건에 맞는 로그를 Seq 순으로 조회합니다
+func (s *SQLStorage) QueryLogs(jobID string, query LogQuery) ([]models.LogEntry, bool) {
+	logs := []models.LogEntry{}
+	exists, err := s.StreamLogs(jobID, query, func(entry models.LogEntry) error {
+		logs = append(logs, entry)
+		return nil
+	})
+	if err != nil {
+		log.Printf("failed to read logs of %s: %v", jobID, err)
+	}
+	return logs, exists
+}
+
+// StreamLogs는 조건에 맞는 로그를 DB에서 읽는 대로 fn에 전달합니다
+// 정규식을 제외한 조건은 SQL로 처리하고, 정규식과 그에 따른 Limit은 읽으면서 적용합니다
+func (s *SQLStorage) StreamLogs(jobID string, query LogQuery, fn func(models.LogEntry) error) (bool, error) {
+	where, args := s.logConditions(jobID, query)
+	statement := `SELECT seq, timestamp, container, message, level FROM logs WHERE ` + where
+
+	if query.Tail > 0 {
+		// 끝에서부터 Tail개를 찾은 뒤 Seq 순으로 되돌림
+		statement += ` ORDER BY seq DESC`
+		if query.Pattern == nil {
+			statement += fmt.Sprintf(` LIMIT %d`, query.Tail)
+		}
+
+		var tail []models.LogEntry
+		err := s.scanLogs(statement, args, func(entry models.LogEntry) (bool, error) {
+			if query.Matches(entry) {
+				tail = append(tail, entry)
+			}
+			return len(tail) < query.Tail, nil
+		})
+		if err != nil {
+			return false, err
+		}
+		if len(tail) == 0 {
+			return s.Exists(jobID), nil
+		}
+
+		slices.Reverse(tail)
+		if query.Limit > 0 && len(tail) > query.Limit {
+			tail = tail[:query.Limit]
+		}
+		for _, entry := range tail {
+			if err := fn(entry); err != nil {
+				return true, err
+			}
+		}
+		return true, nil
+	}
+
+	statement += ` ORDER BY seq`
+	if query.Limit > 0 && query.Pattern == nil {
+		statement += fmt.Sprintf(` LIMIT %d`, query.Limit)
+	}
+
+	found := false
+	visit := query.visitor(fn)
+	err := s.scanLogs(statement, args, func(entry models.LogEntry) (bool, error) {
+		found = true
+		return visit(entry)
+	})
+	if err != nil {
+		return found || s.Exists(jobID), err
+	}
+	return found || s.Exists(jobID), nil
+}
+
+// logConditions는 LogQuery의 조건 중 SQL로 처리할 수 있는 조건을 WHERE 절로 만듭니다
+func (s *SQLStorage) logConditions(jobID string, query LogQuery) (string, []any) {
+	conditions := []string{`job_id = ?`, `seq > ?`}
+	args := []any{jobID, query.SinceSeq}
+
+	if len(query.Containers) > 0 {
+		conditions = append(conditions, `container IN (`+placeholders(len(query.Containers))+`)`)
+		for _, container := range query.Containers {
+			args = append(args, container)
+		}
+	}
+
+	if query.MinLevel != "" {
+		var levels []any
+		for _, level := range []string{"info", "warn", "error"} {
+			if utils.LogLevelSeverity(level) >= utils.LogLevelSeverity(query.MinLevel) {
+				levels = append(levels, level)
+			}
+		}
+		conditions = append(conditions, `level IN (`+placeholders(len(levels))+`)`)
+		args = append(args, levels...)
+	}
+
+	if query.Contains != "" {
+		conditions = append(conditions, fmt.Sprintf(s.dialect.contains, "?"))
+		args = append(args, query.Contains)
+	}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, `timestamp_unix >= ?`)
+		args = append(args, query.Since.Unix())
+	}
+	if !query.Until.IsZero() {
+		conditions = append(conditions, `timestamp_unix < ?`)
+		args = append(args, query.Until.Unix())
+	}
+
+	return strings.Join(conditions, ` AND `), args
+}
+
+// scanLogs는 로그 조회 결과를 한 행씩 visit에 전달합니다
+func (s *SQLStorage) scanLogs(statement string, args []any, visit func(models.LogEntry) (bool, error)) error {
+	rows, err := s.db.Query(s.dialect.rebind(statement), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.LogEntry
+		if err := rows.Scan(&entry.Seq, &entry.Timestamp, &entry.Container, &entry.Message, &entry.Level); err != nil {
+			return err
+		}
+		if more, err := visit(entry); !more || err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// DeleteLogs는 특정 Job의 로그와 상태를 삭제합니다
+func (s *SQLStorage) DeleteLogs(jobID string) {
+	err := s.inTx(func(tx *sql.Tx) error {
+		for _, table := range []string{"logs", "log_heads", "job_statuses"} {
+			if _, err := tx.Exec(s.dialect.rebind(`DELETE FROM `+table+` WHERE job_id = ?`), jobID); err != nil {
+				return err
+			}
+		}
+		return s.publish(tx, jobID)
+	})
+	if err != nil {
+		log.Printf("failed to delete logs of %s: %v", jobID, err)
+	}
+	s.notifier.notify(jobID)
+}
+
+// Exists는 특정 Job의 로그가 존재하는지 확인합니다
+func (s *SQLStorage) Exists(jobID string) bool {
+	var seq int64
+	err := s.db.QueryRow(s.dialect.rebind(`SELECT last_seq FROM log_heads WHERE job_id = ?`), jobID).Scan(&seq)
+	return err == nil
+}
+
+// SaveStatus는 특정 Job의 상태를 저장합니다
+func (s *SQLStorage) SaveStatus(jobID string, status models.JobStatus) {
+	err := s.inTx(func(tx *sql.Tx) error {
+		_, err := tx.Exec(s.dialect.rebind(`INSERT INTO job_statuses (job_id, status, updated_at) VALUES (?, ?, ?)
+			ON CONFLICT (job_id) DO UPDATE SET status = excluded.status, updated_at = excluded.updated_at`),
+			jobID, string(status), time.Now().Format(time.RFC3339))
+		if err != nil {
+			return err
+		}
+		return s.publish(tx, jobID)
+	})
+	if err != nil {
+		log.Printf("failed to save status of %s: %v", jobID, err)
+		return
+	}
+	s.notifier.notify(jobID)
+}
+
+// TransitionStatus는 현재 상태가 from일 때만 상태를 to로 바꾸고 변경 로그를 같은 트랜잭션에서 저장합니다
+// 조건부 UPDATE로 비교와 변경을 함께 처리하므로 다른 replica의 취소와 상태 추적이 서로 덮어쓰지 않습니다
+func (s *SQLStorage) TransitionStatus(jobID string, from, to models.JobStatus, container, message, level string) (bool, error) {
+	now := time.Now().Truncate(time.Second)
+	err := s.inTx(func(tx *sql.Tx) error {
+		result, err := tx.Exec(s.dialect.rebind(`UPDATE job_statuses SET status = ?, updated_at = ? WHERE job_id = ? AND status = ?`),
+			string(to), now.Format(time.RFC3339), jobID, string(from))
+		if err != nil {
+			return err
+		}
+		if updated, err := result.RowsAffected(); err != nil {
+			return err
+		} else if updated == 0 {
+			return errStatusChanged
+		}
+
+		if err := s.insertLog(tx, jobID, now, container, message, level); err != nil {
+			return err
+		}
+		return s.publish(tx, jobID)
+	})
+	if errors.Is(err, errStatusChanged) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to change status of %s: %w", jobID, err)
+	}
+	s.notifier.notify(jobID)
+	return true, nil
+}
+
+// GetStatus는 특정 Job의 상태를 조회합니다
+func (s *SQLStorage) GetStatus(jobID string) (models.JobState, bool) {
+	var state models.JobState
+	err := s.db.QueryRow(s.dialect.rebind(`SELECT status, updated_at FROM job_statuses WHERE job_id = ?`), jobID).
+		Scan(&state.Status, &state.UpdatedAt)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("failed to get status of %s: %v", jobID, err)
+		}
+		return models.JobState{}, false
+	}
+	return state, true
+}
+
+// Subscribe는 특정 Job의 로그나 상태가 변경될 때 신호를 받는 채널을 반환합니다
+// 다른 프로세스의 변경은 dialect가 notify를 지원하고 수신 중일 때만 알립니다
+func (s *SQLStorage) Subscribe(jobID string) (<-chan struct{}, func()) {
+	return s.notifier.subscribe(jobID)
+}
+
+// SaveJob은 Job 메타데이터를 저장합니다
+func (s *SQLStorage) SaveJob(job models.Job) {
+	s.exec("save job "+job.ID, `INSERT INTO jobs
+		(id, name, image_name, namespace, created_at, created_unix, idempotency_key, request_hash, git_url, git_ref, git_commit)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name, image_name = excluded.image_name, namespace = excluded.namespace,
+			created_at = excluded.created_at, created_unix = excluded.created_unix,
+			idempotency_key = excluded.idempotency_key, request_hash = excluded.request_hash,
+			git_url = excluded.git_url, git_ref = excluded.git_ref, git_commit = excluded.git_commit`,
+		jobArgs(job)...)
+}
+
+// RegisterJob은 같은 Idempotency-Key의 Job과 종료되지 않은 같은 이름의 Job이 없을 때만 Job과 pending 상태를 저장합니다
+// 이름 검사는 이름별 잠금(또는 직렬화된 쓰기 트랜잭션) 안에서 하고, 같은 키의 동시 등록은 unique 인덱스가 막습니다
+func (s *SQLStorage) RegisterJob(job models.Job) (models.Job, error) {
+	var existing models.Job
+	var conflict error
+	err := s.inTx(func(tx *sql.Tx) error {
+		if s.dialect.lockJobName != "" {
+			if _, err := tx.Exec(s.dialect.rebind(s.dialect.lockJobName), job.Name); err != nil {
+				return err
+			}
+		}
+
+		if job.IdempotencyKey != "" {
+			found, err := s.findJobTx(tx, `jobs.idempotency_key = ?`, job.IdempotencyKey)
+			if err == nil {
+				existing, conflict = found, ErrDuplicateIdempotencyKey
+				return nil
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+		}
+
+		terminal := make([]any, 0, len(models.TerminalStatuses)+1)
+		terminal = append(terminal, job.Name)
+		for _, status := range models.TerminalStatuses {
+			terminal = append(terminal, string(status))
+		}
+		found, err := s.findJobTx(tx, `jobs.name = ? AND jobs.id IN (SELECT job_id FROM job_statuses WHERE status NOT IN (`+
+			placeholders(len(models.TerminalStatuses))+`)) ORDER BY jobs.id DESC`, terminal...)
+		if err == nil {
+			existing, conflict = found, ErrActiveJobName
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if _, err := tx.Exec(s.dialect.rebind(`INSERT INTO jobs
+			(id, name, image_name, namespace, created_at, created_unix, idempotency_key, request_hash, git_url, git_ref, git_commit)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`), jobArgs(job)...); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(s.dialect.rebind(`INSERT INTO job_statuses (job_id, status, updated_at) VALUES (?, ?, ?)`),
+			job.ID, string(models.JobStatusPending), time.Now().Format(time.RFC3339)); err != nil {
+			return err
+		}
+		return s.publish(tx, job.ID)
+	})
+
+	// 다른 replica가 같은 키로 먼저 커밋했으면 그 Job을 재시도 대상으로 반환
+	if err != nil && job.IdempotencyKey != "" && s.dialect.isUniqueViolation(err) {
+		if found, exists := s.FindByIdempotencyKey(job.IdempotencyKey); exists {
+			return found, ErrDuplicateIdempotencyKey
+		}
+	}
+	if err != nil {
+		return models.Job{}, fmt.Errorf("failed to register job %s: %w", job.Name, err)
+	}
+	if conflict != nil {
+		return existing, conflict
+	}
+	s.notifier.notify(job.ID)
+	return job, nil
+}
+
+// jobArgs는 jobs 테이블에 저장할 Job의 컬럼 값입니다 (INSERT 컬럼 순서와 같아야 함)
+func jobArgs(job models.Job) []any {
+	var createdUnix int64
+	if createdAt, err := time.Parse(time.RFC3339, job.CreatedAt); err == nil {
+		createdUnix = createdAt.Unix()
+	}
+
+	var idempotencyKey sql.NullString
+	if job.IdempotencyKey != "" {
+		idempotencyKey = sql.NullString{String: job.IdempotencyKey, Valid: true}
+	}
+
+	return []any{job.ID, job.Name, job.ImageName, job.Namespace, job.CreatedAt, createdUnix, idempotencyKey, job.RequestHash,
+		job.GitURL, job.GitRef, job.GitCommit}
+}
+
+// jobColumns는 Job 조회 시 선택하는 컬럼입니다 (scanJob과 순서가 같아야 함)
+const jobColumns = `jobs.id, jobs.name, jobs.image_name, jobs.namespace, jobs.created_at,
+	COALESCE(jobs.idempotency_key, ''), jobs.request_hash, jobs.git_url, jobs.git_ref, jobs.git_commit`
+
+// GetJob은 ID로 Job을 조회합니다
+func (s *SQLStorage) GetJob(id string) (models.Job, bool) {
+	return s.findJob(`jobs.id = ?`, id)
+}
+
+// FindByName은 같은 이름으로 가장 최근에 등록된 Job을 조회합니다
+func (s *SQLStorage) FindByName(name string) (models.Job, bool) {
+	return s.findJob(`jobs.name = ? ORDER BY jobs.id DESC`, name)
+}
+
+// FindByIdempotencyKey는 Idempotency-Key로 등록된 Job을 조회합니다
+func (s *SQLStorage) FindByIdempotencyKey(key string) (models.Job, bool) {
+	return s.findJob(`jobs.idempotency_key = ? ORDER BY jobs.id DESC`, key)
+}
+
+// findJobTx는 트랜잭션 안에서 조건에 맞는 첫 번째 Job을 조회합니다 (없으면 sql.ErrNoRows)
+func (s *SQLStorage) findJobTx(tx *sql.Tx, condition string, args ...any) (models.Job, error) {
+	return scanJob(tx.QueryRow(s.dialect.rebind(`SELECT `+jobColumns+` FROM jobs WHERE `+condition+` LIMIT 1`), args...))
+}
+
+// findJob은 조건에 맞는 첫 번째 Job을 조회합니다
+func (s *SQLStorage) findJob(condition string, arg any) (models.Job, bool) {
+	row := s.db.QueryRow(s.dialect.rebind(`SELECT `+jobColumns+` FROM jobs WHERE `+condition+` LIMIT 1`), arg)
+	job, err := scanJob(row)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("failed to find job: %v", err)
+		}
+		return models.Job{}, false
+	}
+	return job, true
+}
+
+// DeleteJob은 Job 메타데이터를 삭제합니다
+func (s *SQLStorage) DeleteJob(id string) {
+	s.exec("delete job "+id, `DELETE FROM jobs WHERE id = ?`, id)
+}
+
+// ListJobs는 조건에 맞는 Job을 정렬하여 최대 query.Limit개 조회합니다
+func (s *SQLStorage) ListJobs(query JobQuery) ([]models.Job, string, error) {
+	conditions := []string{`jobs.name LIKE ? ESCAPE '\'`}
+	args := []any{escapeLike(query.NamePrefix) + "%"}
+
+	if len(query.Statuses) > 0 {
+		conditions = append(conditions, `job_statuses.status IN (`+placeholders(len(query.Statuses))+`)`)
+		for _, status := range query.Statuses {
+			args = append(args, string(status))
+		}
+	}
+	if !query.CreatedAfter.IsZero() {
+		conditions = append(conditions, `jobs.created_unix >= ?`)
+		args = append(args, query.CreatedAfter.Unix())
+	}
+	if !query.CreatedBefore.IsZero() {
+		conditions = append(conditions, `jobs.created_unix < ?`)
+		args = append(args, query.CreatedBefore.Unix())
+	}
+
+	sortColumn, direction, compare := `jobs.id`, `ASC`, `>`
+	if query.SortBy == SortByName {
+		sortColumn = `jobs.name`
+	}
+	if query.Descending {
+		direction, compare = `DESC`, `<`
+	}
+
+	// keyset pagination: 커서의 (정렬 키, ID) 다음부터 조회
+	if query.Cursor != "" {
+		cursor, err := decodeCursor(query.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		conditions = append(conditions, fmt.Sprintf(`(%[1]s %[2]s ? OR (%[1]s = ? AND jobs.id %[2]s ?))`, sortColumn, compare))
+		args = append(args, cursor.Key, cursor.Key, cursor.ID)
+	}
+
+	// Limit보다 하나 더 조회하여 다음 페이지 존재 여부를 확인
+	statement := fmt.Sprintf(`SELECT %s FROM jobs LEFT JOIN job_statuses ON job_statuses.job_id = jobs.id
+		WHERE %s ORDER BY %s %s, jobs.id %s LIMIT %d`,
+		jobColumns, strings.Join(conditions, ` AND `), sortColumn, direction, direction, query.Limit+1)
+
+	rows, err := s.db.Query(s.dialect.rebind(statement), args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	jobs := make([]models.Job, 0, query.Limit)
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(jobs) == query.Limit {
+			return jobs, query.encodeCursor(jobs[len(jobs)-1]), nil
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, "", rows.Err()
+}
+
+// inTx는 fn을 하나의 트랜잭션으로 실행합니다
+func (s *SQLStorage) inTx(fn func(*sql.Tx) error) error {
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// scanJob은 jobColumns 순서의 행을 Job으로 읽습니다
+func scanJob(row interface{ Scan(...any) error }) (models.Job, error) {
+	var job models.Job
+	err := row.Scan(&job.ID, &job.Name, &job.ImageName, &job.Namespace, &job.CreatedAt, &job.IdempotencyKey, &job.RequestHash,
+		&job.GitURL, &job.GitRef, &job.GitCommit)
+	return job, err
+}
+
+// placeholders는 n개의 ? 자리표시자를 쉼표로 이어 반환합니다
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// escapeLike는 LIKE 패턴의 특수 문자를 이스케이프합니다
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteDialect는 SQLite 쿼리 형식입니다
+// 트랜잭션을 BEGIN IMMEDIATE로 시작하므로(_txlock=immediate) 쓰기 트랜잭션은 별도 잠금 없이 직렬화됩니다
+var sqliteDialect = sqlDialect{
+	rebind:   func(query string) string { return query },
+	contains: "instr(message, %s) > 0",
+	isUniqueViolation: func(err error) bool {
+		var sqliteErr *sqlite.Error
+		return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	},
+}
+
+// NewSQLiteStorage는 path의 SQLite 데이터베이스를 사용하는 저장소를 생성합니다
+// 파일이 없으면 새로 만들고, 시작 시 스키마 마이그레이션을 적용합니다
+func NewSQLiteStorage(path string) (*SQLStorage, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	// WAL 모드에서는 로그를 내려받는 동안에도 새 로그를 저장할 수 있음
+	dsn := "file:" + path + "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=synchronous(NORMAL)&_txlock=immediate"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database %s: %w", path, err)
+	}
+
+	s, err := newSQLStorage(db, sqliteDialect)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
@@ -16,3 +16,17 @@ func DetectLogLevel(message string) string {
 
 	return "info"
 }
+
+// LogLevelSeverity는 로그 레벨의 심각도 순서를 반환합니다 (info < warn < error)
+// 알 수 없는 레벨이면 -1을 반환합니다
+func LogLevelSeverity(level string) int {
+	switch level {
+	case "info":
+		return 0
+	case "warn":
+		return 1
+	case "error":
+		return 2
+	}
+	return -1
+}